@@ -0,0 +1,24 @@
+package bundler
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/mrhapile/fluid-diagnose-bundler/pkg/types"
+)
+
+// LoadBaselineManifest reads a previously-generated manifest.json (as
+// referenced by WithBaseline) so its (path, sha256) pairs can be diffed
+// against the bundle currently being built.
+func LoadBaselineManifest(path string) (types.BundleManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return types.BundleManifest{}, fmt.Errorf("failed to read baseline manifest: %w", err)
+	}
+	var manifest types.BundleManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return types.BundleManifest{}, fmt.Errorf("failed to parse baseline manifest: %w", err)
+	}
+	return manifest, nil
+}
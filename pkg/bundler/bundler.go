@@ -3,6 +3,7 @@ package bundler
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"path/filepath"
 	"time"
 
@@ -14,15 +15,63 @@ import (
 type Option func(*config)
 
 type config struct {
-	redact    bool
-	timestamp time.Time
-	outputDir string
+	redact       bool
+	redactPolicy RedactionPolicy
+	redactErr    error
+	redactDryRun bool
+	timestamp    time.Time
+	outputDir    string
+	ociExport    bool
+	compression  Compression
+	signer       Signer
+	baseline     []types.FileEntry
+	baselineErr  error
 }
 
-// WithRedaction enables sensitive data redaction.
+// WithRedaction enables sensitive data redaction using the built-in default
+// policy (see DefaultRedactionPolicy). Use WithRedactionPolicy or
+// WithRedactionRules instead to supply custom rules.
 func WithRedaction() Option {
 	return func(c *config) {
 		c.redact = true
+		c.redactPolicy = DefaultRedactionPolicy()
+	}
+}
+
+// WithRedactionPolicy enables redaction using rules loaded from a YAML
+// policy file at path.
+func WithRedactionPolicy(path string) Option {
+	return func(c *config) {
+		c.redact = true
+		policy, err := LoadRedactionPolicy(path)
+		if err != nil {
+			c.redactErr = err
+			return
+		}
+		c.redactPolicy = policy
+	}
+}
+
+// WithRedactionRules enables redaction using an explicit RedactionPolicy.
+func WithRedactionRules(policy RedactionPolicy) Option {
+	return func(c *config) {
+		c.redact = true
+		compiled, err := policy.compile()
+		if err != nil {
+			c.redactErr = err
+			return
+		}
+		c.redactPolicy = compiled
+	}
+}
+
+// WithRedactionDryRun runs the configured redaction policy without
+// mutating bundle content, instead recording every match it would have made
+// on BundleResult.RedactionReport. Has no effect unless combined with
+// WithRedaction, WithRedactionPolicy, or WithRedactionRules.
+func WithRedactionDryRun() Option {
+	return func(c *config) {
+		c.redactDryRun = true
 	}
 }
 
@@ -41,31 +90,81 @@ func WithOutputDir(path string) Option {
 	}
 }
 
+// WithOCIExport additionally emits the bundle as an OCI image layout
+// (blobs/, oci-layout, index.json) alongside the regular archive, so the
+// bundle can be pushed to any OCI registry with `crane push` / `oras`.
+func WithOCIExport() Option {
+	return func(c *config) {
+		c.ociExport = true
+	}
+}
+
+// WithCompression selects the archive compression algorithm. Defaults to
+// gzip; use CompressionZstd or CompressionNone as needed.
+func WithCompression(c Compression) Option {
+	return func(cfg *config) {
+		cfg.compression = c
+	}
+}
+
+// WithSigning signs the manifest's ContentHash with signer, writing a
+// detached manifest.sig plus a signatures.json alongside the archive so a
+// downstream reader can prove the bundle hasn't been tampered with between
+// generation and analysis. See the verify subpackage's VerifySignature.
+func WithSigning(signer Signer) Option {
+	return func(c *config) {
+		c.signer = signer
+	}
+}
+
+// WithBaseline enables incremental bundling against a prior bundle: files
+// whose (path, sha256) match an entry in the manifest.json at
+// prevManifestPath are written as zero-byte placeholders instead of being
+// repacked, and every file's status relative to it is recorded in
+// delta.json. See pkg/bundler/merge to reconstitute a full bundle from
+// baseline + delta.
+func WithBaseline(prevManifestPath string) Option {
+	return func(c *config) {
+		manifest, err := LoadBaselineManifest(prevManifestPath)
+		if err != nil {
+			c.baselineErr = err
+			return
+		}
+		c.baseline = manifest.Files
+	}
+}
+
 // Build creates a diagnostic bundle from the given input.
 func Build(input types.BundleInput, opts ...Option) (*types.BundleResult, error) {
 	// 1. Configure
 	cfg := &config{
-		timestamp: time.Now(), // Default, can be overridden for determinism
-		outputDir: ".",
+		timestamp:   time.Now(), // Default, can be overridden for determinism
+		outputDir:   ".",
+		compression: CompressionGzip,
 	}
 	for _, opt := range opts {
 		opt(cfg)
 	}
+	if cfg.redactErr != nil {
+		return nil, fmt.Errorf("failed to load redaction policy: %w", cfg.redactErr)
+	}
+	if cfg.baselineErr != nil {
+		return nil, fmt.Errorf("failed to load baseline manifest: %w", cfg.baselineErr)
+	}
 
 	// 2. Initialize components
-	// Determine base directory name
-	// In a real scenario, this might come from input.Metadata.Context
-	baseDir := fmt.Sprintf("fluid-diagnose-%s", cfg.timestamp.Format("20060102-150405"))
+	baseDir := fmt.Sprintf("fluid-diagnose-%s-%s", extractDatasetName(input.Graph), cfg.timestamp.Format("20060102-150405"))
 
 	manifestBuilder := NewManifestBuilder("v1", cfg.timestamp)
 	archiveWriter := NewArchiveWriter(baseDir, cfg.timestamp)
-
-	// Redactor
-	var redactor Redactor
-	if cfg.redact {
-		redactor = newRedactor()
+	archiveWriter.SetCompression(cfg.compression)
+	if cfg.baseline != nil {
+		archiveWriter.SetBaseline(cfg.baseline)
 	}
 
+	// Redaction report, populated only when redaction is enabled.
+	redactionReport := &types.DryRunReport{}
+
 	// Helper to Process and Add a File
 	addFile := func(path string, data interface{}, isJSON bool) error {
 		var content []byte
@@ -73,20 +172,31 @@ func Build(input types.BundleInput, opts ...Option) (*types.BundleResult, error)
 
 		// Redaction phase
 		if cfg.redact {
-			// If it's a map/struct, use structural redaction
-			// If it's bytes, use regex
+			// If it's raw bytes/text, only ValueRules apply (there's no
+			// field name to key off). Everything else is a map/struct, so
+			// run it through structural redaction instead.
 			switch v := data.(type) {
 			case []byte:
-				content = redactor.Redact(v)
+				scrubbed, report := cfg.redactPolicy.Scrub(v, "", cfg.redactDryRun)
+				redactionReport.Actions = append(redactionReport.Actions, report.Actions...)
+				if !cfg.redactDryRun {
+					data = scrubbed
+				}
 			case string:
-				content = []byte(redactor.RedactString(v))
+				scrubbed, report := cfg.redactPolicy.Scrub(v, "", cfg.redactDryRun)
+				redactionReport.Actions = append(redactionReport.Actions, report.Actions...)
+				if !cfg.redactDryRun {
+					data = scrubbed
+				}
 			default:
-				// Structural redaction for JSON/YAML objects
-				cleanData, err := scrubJSON(data)
-				if err != nil {
-					return fmt.Errorf("redaction failed for %s: %w", path, err)
+				scrubbed, report, serr := cfg.redactPolicy.ScrubDocument(data, cfg.redactDryRun)
+				if serr != nil {
+					return fmt.Errorf("redaction failed for %s: %w", path, serr)
+				}
+				redactionReport.Actions = append(redactionReport.Actions, report.Actions...)
+				if !cfg.redactDryRun {
+					data = scrubbed
 				}
-				data = cleanData
 			}
 		}
 
@@ -108,8 +218,6 @@ func Build(input types.BundleInput, opts ...Option) (*types.BundleResult, error)
 			}
 		}
 
-		// Add to manifest and writer
-		manifestBuilder.AddFile(path, int64(len(content)), content)
 		archiveWriter.AddFile(path, content)
 		return nil
 	}
@@ -156,25 +264,71 @@ func Build(input types.BundleInput, opts ...Option) (*types.BundleResult, error)
 		}
 	}
 
-	// 6. Finalize Manifest
-	manifest := manifestBuilder.Build()
-	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+	// 5b. Stream large logs/resources directly into the archive instead of
+	// buffering their content in memory.
+	for filename, src := range input.LogSources {
+		archiveWriter.AddSource(filepath.Join(LogsDir, filename), src)
+	}
+	for path, src := range input.ResourceSources {
+		archiveWriter.AddSource(filepath.Join(ResourcesDir, path), src)
 	}
-	// We add manifest to archive, but NOT to the manifest builder (recursion)
-	archiveWriter.AddFile(ManifestFile, manifestBytes)
 
-	// 7. Write Archive
-	archivePath, size, err := archiveWriter.WriteToDisk(cfg.outputDir)
+	// 6. Write Archive. Every entry is hashed on the fly as it's streamed
+	// out, and those digests feed the manifest once all entries are known,
+	// so manifest.json can be appended after every content entry (followed
+	// only by delta.json, when a baseline is set).
+	var manifest types.BundleManifest
+	archivePath, size, err := archiveWriter.WriteToDisk(cfg.outputDir, func(digests []FileDigest) ([]byte, error) {
+		for _, d := range digests {
+			manifestBuilder.AddFile(d.Path, d.Size, d.SHA256)
+		}
+		manifest = manifestBuilder.Build()
+		return json.MarshalIndent(manifest, "", "  ")
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to write archive: %w", err)
 	}
 
-	return &types.BundleResult{
+	fileCount := manifest.TotalFiles + 1 // +1 for manifest.json
+	if cfg.baseline != nil {
+		fileCount++ // +1 for delta.json
+	}
+	result := &types.BundleResult{
 		ArchivePath: archivePath,
-		FileCount:   manifest.TotalFiles + 1, // +1 for manifest.json
+		FileCount:   fileCount,
 		Manifest:    manifest,
 		SizeBytes:   size,
-	}, nil
+	}
+	if cfg.redact {
+		result.RedactionReport = redactionReport
+	}
+	if cfg.baseline != nil {
+		result.DeltaEntries = archiveWriter.DeltaEntries()
+	}
+
+	// 7. Optionally sign the manifest's ContentHash, so a downstream reader
+	// can prove the bundle hasn't been tampered with in transit.
+	if cfg.signer != nil {
+		if err := signManifest(cfg.outputDir, manifest.ContentHash, cfg.signer); err != nil {
+			return nil, err
+		}
+		result.SignaturePath = filepath.Join(cfg.outputDir, SignatureFile)
+		result.SignaturesIndexPath = filepath.Join(cfg.outputDir, SignaturesIndexFile)
+	}
+
+	// 8. Optionally also emit an OCI image layout, reusing the tar.gz we just
+	// wrote to disk as the image's single layer.
+	if cfg.ociExport {
+		layerGz, err := os.ReadFile(archivePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive for oci export: %w", err)
+		}
+		ociPath, err := writeOCILayout(filepath.Dir(archivePath), baseDir, layerGz, cfg.compression, manifest, input.Diagnosis)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write oci layout: %w", err)
+		}
+		result.OCILayoutPath = ociPath
+	}
+
+	return result, nil
 }
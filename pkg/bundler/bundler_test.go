@@ -1,14 +1,33 @@
 package bundler_test
 
 import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/mrhapile/fluid-diagnose-bundler/pkg/bundler"
+	"github.com/mrhapile/fluid-diagnose-bundler/pkg/bundler/verify"
 	"github.com/mrhapile/fluid-diagnose-bundler/pkg/types"
 )
 
+// stringSource is a minimal types.Source backed by an in-memory string,
+// used to exercise the streaming AddSource path in tests without needing
+// an actual large file on disk.
+type stringSource struct {
+	content string
+}
+
+func (s stringSource) Open() (io.ReadCloser, int64, error) {
+	return io.NopCloser(bytes.NewReader([]byte(s.content))), int64(len(s.content)), nil
+}
+
 func TestBuild(t *testing.T) {
 	// Create temp output dir
 	outDir, err := os.MkdirTemp("", "fluid-test")
@@ -68,3 +87,313 @@ func TestBuild(t *testing.T) {
 		t.Errorf("Archive not found: %s", result.ArchivePath)
 	}
 }
+
+func TestBuildNamesArchiveAfterDataset(t *testing.T) {
+	outDir, err := os.MkdirTemp("", "fluid-test-dataset-name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outDir)
+
+	// Shaped the way pkg/collector populates Graph for a real Dataset.
+	input := types.BundleInput{
+		Graph: types.ResourceGraph{
+			"kind": "Dataset",
+			"dataset": map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "imagenet"},
+			},
+		},
+		Diagnosis: types.DiagnosticResult{Issues: []types.Issue{}},
+		Metadata:  types.BundleMetadata{Environment: "test"},
+	}
+
+	result, err := bundler.Build(input,
+		bundler.WithTimestamp(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)),
+		bundler.WithOutputDir(outDir),
+	)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if !strings.Contains(filepath.Base(result.ArchivePath), "imagenet") {
+		t.Errorf("expected archive name to contain the dataset name, got %s", result.ArchivePath)
+	}
+}
+
+func TestBuildNamesArchiveUnknownWithoutDataset(t *testing.T) {
+	outDir, err := os.MkdirTemp("", "fluid-test-no-dataset-name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outDir)
+
+	input := types.BundleInput{
+		Diagnosis: types.DiagnosticResult{Issues: []types.Issue{}},
+		Metadata:  types.BundleMetadata{Environment: "test"},
+	}
+
+	result, err := bundler.Build(input,
+		bundler.WithTimestamp(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)),
+		bundler.WithOutputDir(outDir),
+	)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if !strings.Contains(filepath.Base(result.ArchivePath), "unknown") {
+		t.Errorf("expected archive name to fall back to \"unknown\", got %s", result.ArchivePath)
+	}
+}
+
+func TestBuildWithOCIExport(t *testing.T) {
+	outDir, err := os.MkdirTemp("", "fluid-test-oci")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outDir)
+
+	input := types.BundleInput{
+		Graph: types.ResourceGraph{"kind": "Dataset"},
+		Diagnosis: types.DiagnosticResult{
+			Issues: []types.Issue{{Level: "warning", Message: "demo"}},
+		},
+		Metadata: types.BundleMetadata{Environment: "test"},
+	}
+
+	fixedTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	result, err := bundler.Build(input,
+		bundler.WithTimestamp(fixedTime),
+		bundler.WithOutputDir(outDir),
+		bundler.WithOCIExport(),
+	)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if result.OCILayoutPath == "" {
+		t.Fatal("OCILayoutPath is empty")
+	}
+	if _, err := os.Stat(filepath.Join(result.OCILayoutPath, "oci-layout")); os.IsNotExist(err) {
+		t.Errorf("oci-layout not found under %s", result.OCILayoutPath)
+	}
+	if _, err := os.Stat(filepath.Join(result.OCILayoutPath, "index.json")); os.IsNotExist(err) {
+		t.Errorf("index.json not found under %s", result.OCILayoutPath)
+	}
+	if _, err := os.Stat(filepath.Join(result.OCILayoutPath, "blobs", "sha256")); os.IsNotExist(err) {
+		t.Errorf("blobs/sha256 not found under %s", result.OCILayoutPath)
+	}
+}
+
+func TestBuildWithStreamedSourceAndZstd(t *testing.T) {
+	outDir, err := os.MkdirTemp("", "fluid-test-stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outDir)
+
+	input := types.BundleInput{
+		Graph:     types.ResourceGraph{"kind": "Dataset"},
+		Diagnosis: types.DiagnosticResult{Issues: []types.Issue{}},
+		Metadata:  types.BundleMetadata{Environment: "test"},
+		LogSources: map[string]types.Source{
+			"fuse.log": stringSource{content: "streamed log content\n"},
+		},
+	}
+
+	fixedTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	result, err := bundler.Build(input,
+		bundler.WithTimestamp(fixedTime),
+		bundler.WithOutputDir(outDir),
+		bundler.WithCompression(bundler.CompressionZstd),
+	)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if filepath.Ext(result.ArchivePath) != ".zst" {
+		t.Errorf("expected a .zst archive, got %s", result.ArchivePath)
+	}
+
+	found := false
+	for _, f := range result.Manifest.Files {
+		if f.Path == filepath.Join(bundler.LogsDir, "fuse.log") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("streamed log source missing from manifest")
+	}
+}
+
+func TestBuildWithSigningProducesVerifiableSignature(t *testing.T) {
+	outDir, err := os.MkdirTemp("", "fluid-test-sign")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outDir)
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	input := types.BundleInput{
+		Graph:     types.ResourceGraph{"kind": "Dataset"},
+		Diagnosis: types.DiagnosticResult{Issues: []types.Issue{}},
+		Metadata:  types.BundleMetadata{Environment: "test"},
+	}
+
+	result, err := bundler.Build(input,
+		bundler.WithTimestamp(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)),
+		bundler.WithOutputDir(outDir),
+		bundler.WithSigning(bundler.NewEd25519Signer(priv, "test-key")),
+	)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if result.SignaturePath == "" || result.SignaturesIndexPath == "" {
+		t.Fatal("expected signature paths to be set")
+	}
+
+	b, err := verify.Open(result.ArchivePath)
+	if err != nil {
+		t.Fatalf("verify.Open failed: %v", err)
+	}
+	defer b.Close()
+
+	if err := b.VerifySignature(pub); err != nil {
+		t.Errorf("VerifySignature failed: %v", err)
+	}
+
+	otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	if err := b.VerifySignature(otherPub); err == nil {
+		t.Error("expected VerifySignature to fail for the wrong public key")
+	}
+}
+
+func TestBuildWithBaselineMarksDeltaStatus(t *testing.T) {
+	outDir, err := os.MkdirTemp("", "fluid-test-baseline")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outDir)
+
+	baselineInput := types.BundleInput{
+		Graph:     types.ResourceGraph{"kind": "Dataset"},
+		Diagnosis: types.DiagnosticResult{Issues: []types.Issue{}},
+		Metadata:  types.BundleMetadata{Environment: "test"},
+		Logs: map[string][]byte{
+			"fuse.log":   []byte("line 1\n"),
+			"worker.log": []byte("worker started\n"),
+		},
+	}
+
+	baselineResult, err := bundler.Build(baselineInput,
+		bundler.WithTimestamp(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)),
+		bundler.WithOutputDir(outDir),
+	)
+	if err != nil {
+		t.Fatalf("baseline Build failed: %v", err)
+	}
+	baselineManifestPath := filepath.Join(outDir, "baseline-manifest.json")
+	manifestBytes, err := json.MarshalIndent(baselineResult.Manifest, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(baselineManifestPath, manifestBytes, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	incrementalInput := types.BundleInput{
+		Graph:     types.ResourceGraph{"kind": "Dataset"},
+		Diagnosis: types.DiagnosticResult{Issues: []types.Issue{}},
+		Metadata:  types.BundleMetadata{Environment: "test"},
+		Logs: map[string][]byte{
+			"fuse.log": []byte("line 1\nline 2\n"), // modified
+			"new.log":  []byte("fresh\n"),          // added
+			// worker.log is dropped: removed
+		},
+	}
+
+	result, err := bundler.Build(incrementalInput,
+		bundler.WithTimestamp(time.Date(2024, 1, 1, 12, 5, 0, 0, time.UTC)),
+		bundler.WithOutputDir(outDir),
+		bundler.WithBaseline(baselineManifestPath),
+	)
+	if err != nil {
+		t.Fatalf("incremental Build failed: %v", err)
+	}
+
+	statusByPath := make(map[string]types.DeltaStatus)
+	for _, d := range result.DeltaEntries {
+		statusByPath[d.Path] = d.Status
+	}
+
+	graphPath := bundler.GraphFile
+	if statusByPath[graphPath] != types.DeltaUnchanged {
+		t.Errorf("expected %s to be unchanged, got %s", graphPath, statusByPath[graphPath])
+	}
+	fuseLogPath := filepath.Join(bundler.LogsDir, "fuse.log")
+	if statusByPath[fuseLogPath] != types.DeltaModified {
+		t.Errorf("expected %s to be modified, got %s", fuseLogPath, statusByPath[fuseLogPath])
+	}
+	newLogPath := filepath.Join(bundler.LogsDir, "new.log")
+	if statusByPath[newLogPath] != types.DeltaAdded {
+		t.Errorf("expected %s to be added, got %s", newLogPath, statusByPath[newLogPath])
+	}
+	workerLogPath := filepath.Join(bundler.LogsDir, "worker.log")
+	if statusByPath[workerLogPath] != types.DeltaRemoved {
+		t.Errorf("expected %s to be removed, got %s", workerLogPath, statusByPath[workerLogPath])
+	}
+
+	b, err := verify.Open(result.ArchivePath)
+	if err != nil {
+		t.Fatalf("verify.Open failed: %v", err)
+	}
+	defer b.Close()
+
+	r, err := b.File(fuseLogPath)
+	if err != nil {
+		t.Fatalf("File failed: %v", err)
+	}
+	content, err := io.ReadAll(r)
+	r.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "line 1\nline 2\n" {
+		t.Errorf("unexpected modified content: %q", content)
+	}
+
+	// The unchanged graph.json is written as a zero-byte placeholder.
+	r, err = b.File(graphPath)
+	if err != nil {
+		t.Fatalf("File failed: %v", err)
+	}
+	content, err = io.ReadAll(r)
+	r.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(content) != 0 {
+		t.Errorf("expected unchanged %s to be a zero-byte placeholder, got %d bytes", graphPath, len(content))
+	}
+
+	// The incremental bundle's manifest must describe what's actually on
+	// disk (the zero-byte placeholders), not the baseline's real content,
+	// or verify.Verify on the incremental archive alone would flag every
+	// unchanged file as tampered.
+	report, err := verify.Verify(result.ArchivePath)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if !report.OK {
+		t.Errorf("expected incremental bundle to verify clean on its own, got %+v", report)
+	}
+}
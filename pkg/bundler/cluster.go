@@ -0,0 +1,26 @@
+package bundler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mrhapile/fluid-diagnose-bundler/pkg/collector"
+	"github.com/mrhapile/fluid-diagnose-bundler/pkg/types"
+)
+
+// BuildFromCluster collects a BundleInput straight from a live cluster via
+// collector.NewKubeCollector(opts) and builds a bundle from it, so callers
+// don't have to assemble BundleInput by hand.
+func BuildFromCluster(ctx context.Context, opts collector.CollectorOptions, buildOpts ...Option) (*types.BundleResult, error) {
+	kc, err := collector.NewKubeCollector(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kube collector: %w", err)
+	}
+
+	input, err := kc.Collect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect bundle input: %w", err)
+	}
+
+	return Build(input, buildOpts...)
+}
@@ -17,6 +17,11 @@ const (
 	ResourcesDir  = "resources"
 	LogsDir       = "logs"
 	MetadataDir   = "metadata"
+
+	// DeltaFile is the delta manifest appended to incremental bundles built
+	// with WithBaseline, recording each file's status relative to the
+	// baseline. Absent from bundles built without a baseline.
+	DeltaFile = "delta.json"
 )
 
 // Layout holds the mapping of logical content to archive paths.
@@ -47,10 +52,28 @@ func (l *Layout) getSortedPaths() []string {
 	return paths
 }
 
-// Helper to determine dataset name from graph or metadata if not provided
+// extractDatasetName pulls the dataset name out of a ResourceGraph built
+// around a Fluid Dataset (see pkg/collector), so the archive's base
+// directory can be named after it instead of just a timestamp. Returns
+// "unknown" for graphs that aren't rooted at a Dataset, or that are missing
+// the name.
 func extractDatasetName(graph types.ResourceGraph) string {
-	// Heuristic: check if graph has a root dataset object
-	// For now, default to "unknown" if not found
-	// In a real implementation this would parse the graph
-	return "unknown"
+	kind, _ := graph["kind"].(string)
+	if kind != "Dataset" {
+		return "unknown"
+	}
+
+	dataset, ok := graph["dataset"].(map[string]interface{})
+	if !ok {
+		return "unknown"
+	}
+	metadata, ok := dataset["metadata"].(map[string]interface{})
+	if !ok {
+		return "unknown"
+	}
+	name, ok := metadata["name"].(string)
+	if !ok || name == "" {
+		return "unknown"
+	}
+	return name
 }
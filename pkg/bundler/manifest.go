@@ -3,6 +3,7 @@ package bundler
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"sort"
 	"time"
 
 	"github.com/mrhapile/fluid-diagnose-bundler/pkg/types"
@@ -22,23 +23,59 @@ func NewManifestBuilder(version string, ts time.Time) *ManifestBuilder {
 	}
 }
 
-func (mb *ManifestBuilder) AddFile(path string, size int64, data []byte) {
-	hash := sha256.Sum256(data)
+// AddFile records a file's metadata in the manifest. sha256Hex is the
+// caller-computed digest of the file's content (see ArchiveWriter, which
+// streams and hashes files on the fly rather than buffering them).
+func (mb *ManifestBuilder) AddFile(path string, size int64, sha256Hex string) {
 	entry := types.FileEntry{
 		Path:   path,
 		Size:   size,
-		SHA256: hex.EncodeToString(hash[:]),
+		SHA256: sha256Hex,
 	}
 	mb.manifest.Files = append(mb.manifest.Files, entry)
 	mb.manifest.TotalFiles++
 }
 
 func (mb *ManifestBuilder) Build() types.BundleManifest {
-	// Compute global content hash if needed (e.g. hash of concatenated file hashes)
-	hasher := sha256.New()
-	for _, f := range mb.manifest.Files {
-		hasher.Write([]byte(f.SHA256))
-	}
-	mb.manifest.ContentHash = hex.EncodeToString(hasher.Sum(nil))
+	mb.manifest.ContentHash = ComputeContentHash(mb.manifest.Files)
 	return mb.manifest
 }
+
+// ComputeContentHash derives BundleManifest.ContentHash: a Merkle root over
+// files sorted by path, so the result is stable across reruns regardless of
+// the order files were added in (unlike a hash of concatenated digests,
+// which also let two files silently swap hashes and still match). Each leaf
+// hashes a file's (path, sha256) pair so the tree commits to filenames as
+// well as content; see pkg/bundler/verify, which recomputes this from
+// freshly-read file content to check for tampering.
+func ComputeContentHash(files []types.FileEntry) string {
+	if len(files) == 0 {
+		return ""
+	}
+
+	sorted := make([]types.FileEntry, len(files))
+	copy(sorted, files)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Path < sorted[j].Path })
+
+	nodes := make([][32]byte, len(sorted))
+	for i, f := range sorted {
+		nodes[i] = sha256.Sum256([]byte(f.Path + "\x00" + f.SHA256))
+	}
+
+	for len(nodes) > 1 {
+		next := make([][32]byte, 0, (len(nodes)+1)/2)
+		for i := 0; i < len(nodes); i += 2 {
+			if i+1 == len(nodes) {
+				// Odd node out: carry it up unchanged instead of duplicating
+				// it, so the root doesn't depend on an arbitrary padding rule.
+				next = append(next, nodes[i])
+				continue
+			}
+			combined := append(append([]byte{}, nodes[i][:]...), nodes[i+1][:]...)
+			next = append(next, sha256.Sum256(combined))
+		}
+		nodes = next
+	}
+
+	return hex.EncodeToString(nodes[0][:])
+}
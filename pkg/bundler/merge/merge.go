@@ -0,0 +1,94 @@
+// Package merge reconstitutes a full bundle from a baseline archive and an
+// incremental archive built against it with bundler.WithBaseline.
+package merge
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/mrhapile/fluid-diagnose-bundler/pkg/bundler"
+	"github.com/mrhapile/fluid-diagnose-bundler/pkg/bundler/verify"
+	"github.com/mrhapile/fluid-diagnose-bundler/pkg/types"
+)
+
+// Merge reads baselinePath and incrementalPath (an archive built against it
+// via bundler.WithBaseline), and writes a new, full archive under outputDir
+// containing every file at its current content: "unchanged" files are
+// pulled from the baseline, "modified"/"added" files are taken from the
+// incremental bundle, and "removed" files are dropped. ts sets the merged
+// archive's timestamp, for deterministic output.
+func Merge(baselinePath, incrementalPath, outputDir string, ts time.Time) (*types.BundleResult, error) {
+	baseline, err := verify.Open(baselinePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open baseline bundle: %w", err)
+	}
+	defer baseline.Close()
+
+	incremental, err := verify.Open(incrementalPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open incremental bundle: %w", err)
+	}
+	defer incremental.Close()
+
+	deltaR, err := incremental.File(bundler.DeltaFile)
+	if err != nil {
+		return nil, fmt.Errorf("incremental bundle has no %s (was it built with WithBaseline?): %w", bundler.DeltaFile, err)
+	}
+	defer deltaR.Close()
+
+	var delta []types.DeltaEntry
+	if err := json.NewDecoder(deltaR).Decode(&delta); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", bundler.DeltaFile, err)
+	}
+
+	baseDir := fmt.Sprintf("fluid-diagnose-merged-%s", ts.Format("20060102-150405"))
+	archiveWriter := bundler.NewArchiveWriter(baseDir, ts)
+	manifestBuilder := bundler.NewManifestBuilder("v1", ts)
+
+	for _, entry := range delta {
+		var src *verify.Bundle
+		switch entry.Status {
+		case types.DeltaUnchanged:
+			src = baseline
+		case types.DeltaModified, types.DeltaAdded:
+			src = incremental
+		case types.DeltaRemoved:
+			continue
+		default:
+			return nil, fmt.Errorf("unknown delta status %q for %s", entry.Status, entry.Path)
+		}
+
+		r, err := src.File(entry.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s file %s: %w", entry.Status, entry.Path, err)
+		}
+		content, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", entry.Path, err)
+		}
+
+		archiveWriter.AddFile(entry.Path, content)
+	}
+
+	var manifest types.BundleManifest
+	archivePath, size, err := archiveWriter.WriteToDisk(outputDir, func(digests []bundler.FileDigest) ([]byte, error) {
+		for _, d := range digests {
+			manifestBuilder.AddFile(d.Path, d.Size, d.SHA256)
+		}
+		manifest = manifestBuilder.Build()
+		return json.MarshalIndent(manifest, "", "  ")
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to write merged archive: %w", err)
+	}
+
+	return &types.BundleResult{
+		ArchivePath: archivePath,
+		FileCount:   manifest.TotalFiles + 1, // +1 for manifest.json
+		Manifest:    manifest,
+		SizeBytes:   size,
+	}, nil
+}
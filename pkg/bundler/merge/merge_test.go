@@ -0,0 +1,136 @@
+package merge_test
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mrhapile/fluid-diagnose-bundler/pkg/bundler"
+	"github.com/mrhapile/fluid-diagnose-bundler/pkg/bundler/merge"
+	"github.com/mrhapile/fluid-diagnose-bundler/pkg/bundler/verify"
+	"github.com/mrhapile/fluid-diagnose-bundler/pkg/types"
+)
+
+func TestMergeReconstitutesFullBundle(t *testing.T) {
+	outDir, err := os.MkdirTemp("", "fluid-test-merge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outDir)
+
+	baselineInput := types.BundleInput{
+		Graph:     types.ResourceGraph{"kind": "Dataset"},
+		Diagnosis: types.DiagnosticResult{Issues: []types.Issue{}},
+		Metadata:  types.BundleMetadata{Environment: "test"},
+		Logs: map[string][]byte{
+			"fuse.log":   []byte("line 1\n"),
+			"worker.log": []byte("worker started\n"),
+		},
+	}
+	baselineResult, err := bundler.Build(baselineInput,
+		bundler.WithTimestamp(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)),
+		bundler.WithOutputDir(outDir),
+	)
+	if err != nil {
+		t.Fatalf("baseline Build failed: %v", err)
+	}
+
+	baselineManifestPath := filepath.Join(outDir, "baseline-manifest.json")
+	manifestBytes, err := json.MarshalIndent(baselineResult.Manifest, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(baselineManifestPath, manifestBytes, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	incrementalInput := types.BundleInput{
+		Graph:     types.ResourceGraph{"kind": "Dataset"},
+		Diagnosis: types.DiagnosticResult{Issues: []types.Issue{}},
+		Metadata:  types.BundleMetadata{Environment: "test"},
+		Logs: map[string][]byte{
+			"fuse.log": []byte("line 1\nline 2\n"), // modified
+			"new.log":  []byte("fresh\n"),          // added
+			// worker.log is dropped: removed
+		},
+	}
+	incrementalResult, err := bundler.Build(incrementalInput,
+		bundler.WithTimestamp(time.Date(2024, 1, 1, 12, 5, 0, 0, time.UTC)),
+		bundler.WithOutputDir(outDir),
+		bundler.WithBaseline(baselineManifestPath),
+	)
+	if err != nil {
+		t.Fatalf("incremental Build failed: %v", err)
+	}
+
+	mergedResult, err := merge.Merge(baselineResult.ArchivePath, incrementalResult.ArchivePath, outDir,
+		time.Date(2024, 1, 1, 12, 10, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+
+	merged, err := verify.Open(mergedResult.ArchivePath)
+	if err != nil {
+		t.Fatalf("verify.Open failed: %v", err)
+	}
+	defer merged.Close()
+
+	readFile := func(path string) string {
+		t.Helper()
+		r, err := merged.File(path)
+		if err != nil {
+			t.Fatalf("File(%s) failed: %v", path, err)
+		}
+		content, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(content)
+	}
+
+	// fuse.log was modified: the merged bundle should carry the
+	// incremental bundle's content, not the baseline's.
+	if got := readFile(filepath.Join(bundler.LogsDir, "fuse.log")); got != "line 1\nline 2\n" {
+		t.Errorf("fuse.log: got %q", got)
+	}
+	// new.log was added in the incremental bundle.
+	if got := readFile(filepath.Join(bundler.LogsDir, "new.log")); got != "fresh\n" {
+		t.Errorf("new.log: got %q", got)
+	}
+	// worker.log was dropped from the incremental input, so it should be
+	// missing from the merged bundle entirely.
+	if _, err := merged.File(filepath.Join(bundler.LogsDir, "worker.log")); err == nil {
+		t.Error("expected worker.log to be absent from the merged bundle")
+	}
+	// graph.json was unchanged, so its content should be pulled back from
+	// the baseline rather than the incremental bundle's zero-byte placeholder.
+	baseline, err := verify.Open(baselineResult.ArchivePath)
+	if err != nil {
+		t.Fatalf("verify.Open(baseline) failed: %v", err)
+	}
+	defer baseline.Close()
+	r, err := baseline.File(bundler.GraphFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantGraph, err := io.ReadAll(r)
+	r.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(bundler.GraphFile); got != string(wantGraph) {
+		t.Errorf("graph.json: got %q, want %q", got, wantGraph)
+	}
+
+	report, err := verify.Verify(mergedResult.ArchivePath)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if !report.OK {
+		t.Errorf("expected merged bundle to verify clean, got %+v", report)
+	}
+}
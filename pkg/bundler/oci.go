@@ -0,0 +1,170 @@
+package bundler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mrhapile/fluid-diagnose-bundler/pkg/types"
+)
+
+const (
+	ociLayoutVersionFile = "oci-layout"
+	ociIndexFile         = "index.json"
+	ociBlobsDir          = "blobs/sha256"
+
+	ociMediaTypeImageIndex    = "application/vnd.oci.image.index.v1+json"
+	ociMediaTypeImageManifest = "application/vnd.oci.image.manifest.v1+json"
+	ociMediaTypeImageConfig   = "application/vnd.oci.image.config.v1+json"
+	ociMediaTypeLayerGzip     = "application/vnd.oci.image.layer.v1.tar+gzip"
+	ociMediaTypeLayerZstd     = "application/vnd.oci.image.layer.v1.tar+zstd"
+	ociMediaTypeLayerTar      = "application/vnd.oci.image.layer.v1.tar"
+)
+
+// layerMediaType returns the OCI layer media type matching the archive's
+// compression, so the image manifest accurately describes how to decode the
+// layer blob.
+func layerMediaType(compression Compression) string {
+	switch compression {
+	case CompressionZstd:
+		return ociMediaTypeLayerZstd
+	case CompressionNone:
+		return ociMediaTypeLayerTar
+	default:
+		return ociMediaTypeLayerGzip
+	}
+}
+
+// ociDescriptor mirrors the OCI content descriptor used in image manifests and indexes.
+type ociDescriptor struct {
+	MediaType string `json:"mediaType"`
+	Digest    string `json:"digest"`
+	Size      int64  `json:"size"`
+}
+
+// ociImageManifest is a minimal OCI image manifest referencing a single config
+// blob and a single tar layer blob (gzip, zstd, or uncompressed, matching the
+// archive's compression).
+type ociImageManifest struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	MediaType     string          `json:"mediaType"`
+	Config        ociDescriptor   `json:"config"`
+	Layers        []ociDescriptor `json:"layers"`
+}
+
+// ociImageIndex is the top-level entrypoint of an OCI image layout.
+type ociImageIndex struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	MediaType     string          `json:"mediaType"`
+	Manifests     []ociDescriptor `json:"manifests"`
+}
+
+// ociImageConfig embeds a summary of the bundle manifest as OCI image config.
+// It is not a runnable container config; it exists purely as a content-addressed
+// carrier for bundle metadata so registries and `oras`/`crane` consumers can
+// inspect a bundle without unpacking the layer.
+type ociImageConfig struct {
+	Version         string    `json:"version"`
+	GeneratedAt     time.Time `json:"generatedAt"`
+	TotalFiles      int       `json:"totalFiles"`
+	DiagnosisIssues int       `json:"diagnosisIssues"`
+	DiagnosisScore  int       `json:"diagnosisScore"`
+}
+
+// writeOCILayout writes an OCI image layout (blobs/, oci-layout, index.json)
+// under outputDir/<baseDir>-oci, using layerContent (the already-produced
+// archive bytes) as the bundle's single image layer.
+// It returns the absolute path to the layout directory.
+func writeOCILayout(outputDir, baseDir string, layerContent []byte, compression Compression, manifest types.BundleManifest, diagnosis types.DiagnosticResult) (string, error) {
+	layoutDir := filepath.Join(outputDir, baseDir+"-oci")
+	blobsDir := filepath.Join(layoutDir, ociBlobsDir)
+	if err := os.MkdirAll(blobsDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create oci blobs directory: %w", err)
+	}
+
+	layerDigest, err := writeOCIBlob(blobsDir, layerContent)
+	if err != nil {
+		return "", fmt.Errorf("failed to write layer blob: %w", err)
+	}
+
+	cfg := ociImageConfig{
+		Version:         manifest.Version,
+		GeneratedAt:     manifest.GeneratedAt,
+		TotalFiles:      manifest.TotalFiles,
+		DiagnosisIssues: len(diagnosis.Issues),
+		DiagnosisScore:  diagnosis.Score,
+	}
+	cfgBytes, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal oci image config: %w", err)
+	}
+	configDigest, err := writeOCIBlob(blobsDir, cfgBytes)
+	if err != nil {
+		return "", fmt.Errorf("failed to write config blob: %w", err)
+	}
+
+	imageManifest := ociImageManifest{
+		SchemaVersion: 2,
+		MediaType:     ociMediaTypeImageManifest,
+		Config: ociDescriptor{
+			MediaType: ociMediaTypeImageConfig,
+			Digest:    configDigest,
+			Size:      int64(len(cfgBytes)),
+		},
+		Layers: []ociDescriptor{
+			{
+				MediaType: layerMediaType(compression),
+				Digest:    layerDigest,
+				Size:      int64(len(layerContent)),
+			},
+		},
+	}
+	manifestBytes, err := json.MarshalIndent(imageManifest, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal oci image manifest: %w", err)
+	}
+	manifestDigest, err := writeOCIBlob(blobsDir, manifestBytes)
+	if err != nil {
+		return "", fmt.Errorf("failed to write manifest blob: %w", err)
+	}
+
+	index := ociImageIndex{
+		SchemaVersion: 2,
+		MediaType:     ociMediaTypeImageIndex,
+		Manifests: []ociDescriptor{
+			{
+				MediaType: ociMediaTypeImageManifest,
+				Digest:    manifestDigest,
+				Size:      int64(len(manifestBytes)),
+			},
+		},
+	}
+	indexBytes, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal oci index: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(layoutDir, ociIndexFile), indexBytes, 0644); err != nil {
+		return "", fmt.Errorf("failed to write index.json: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(layoutDir, ociLayoutVersionFile), []byte(`{"imageLayoutVersion":"1.0.0"}`), 0644); err != nil {
+		return "", fmt.Errorf("failed to write oci-layout: %w", err)
+	}
+
+	return layoutDir, nil
+}
+
+// writeOCIBlob writes content into blobsDir keyed by its sha256 digest and
+// returns the digest in "sha256:<hex>" form.
+func writeOCIBlob(blobsDir string, content []byte) (string, error) {
+	sum := sha256.Sum256(content)
+	digest := hex.EncodeToString(sum[:])
+	if err := os.WriteFile(filepath.Join(blobsDir, digest), content, 0644); err != nil {
+		return "", err
+	}
+	return "sha256:" + digest, nil
+}
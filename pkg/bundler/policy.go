@@ -0,0 +1,170 @@
+package bundler
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// RedactionPolicy declaratively describes how sensitive content is found
+// and masked when building a bundle. It replaces a single hardcoded key
+// list and regex with rules that can be tuned per deployment: which field
+// names are always sensitive, which value shapes look like secrets
+// regardless of key name, and which fields to keep or drop entirely for
+// specific Kubernetes kinds (e.g. a Secret's .data vs a ConfigMap's .data).
+type RedactionPolicy struct {
+	// KeyRules redact any structured field whose name matches.
+	KeyRules []KeyRule `yaml:"keyRules"`
+	// ValueRules redact (or partially mask) values matching a pattern,
+	// independent of field name. Also applied to raw, unstructured text
+	// (e.g. plain log lines) where there's no field name at all.
+	ValueRules []ValueRule `yaml:"valueRules"`
+	// Selectors are JSONPath-style rules that keep or drop specific fields
+	// wholesale, regardless of the key/value rules above.
+	Selectors []Selector `yaml:"selectors"`
+	// KindOverrides customize redaction for a specific Kubernetes `kind`
+	// (e.g. fully dropping a Secret's .data while keeping a ConfigMap's).
+	KindOverrides map[string]KindOverride `yaml:"kindOverrides"`
+}
+
+// KeyMatchMode selects how a KeyRule.Pattern is interpreted against a field name.
+type KeyMatchMode string
+
+const (
+	KeyMatchExact     KeyMatchMode = "exact"
+	KeyMatchSubstring KeyMatchMode = "substring"
+	KeyMatchGlob      KeyMatchMode = "glob"
+	KeyMatchRegex     KeyMatchMode = "regex"
+)
+
+// KeyRule redacts any structured field whose name matches Pattern under Match.
+type KeyRule struct {
+	Pattern string       `yaml:"pattern"`
+	Match   KeyMatchMode `yaml:"match"`
+
+	// compiled is Pattern, precompiled by RedactionPolicy.compile; only set
+	// when Match is KeyMatchRegex.
+	compiled *regexp.Regexp
+}
+
+// ValueRule redacts any value matching Pattern. If MaskTemplate is set, its
+// capture-group references (e.g. "$1") are substituted into it instead of a
+// flat "[REDACTED]" -- letting a value be partially masked, such as keeping
+// the key name or the last few digits of a card number.
+type ValueRule struct {
+	Pattern      string `yaml:"pattern"`
+	MaskTemplate string `yaml:"maskTemplate,omitempty"`
+
+	// compiled is Pattern, precompiled by RedactionPolicy.compile.
+	compiled *regexp.Regexp
+}
+
+// SelectorAction describes what a Selector does to the fields it matches.
+type SelectorAction string
+
+const (
+	SelectorKeep SelectorAction = "keep"
+	SelectorDrop SelectorAction = "drop"
+)
+
+// Selector is a JSONPath-style rule, e.g. "$.data.*" or
+// "$.spec.runtime.tieredstore.levels[*].mediumtype". It implements a
+// practical subset of JSONPath -- dot-separated field access, a "*" segment
+// meaning any key, and "[*]" meaning any array element -- not a full
+// JSONPath implementation.
+type Selector struct {
+	Path   string         `yaml:"path"`
+	Action SelectorAction `yaml:"action"`
+	// Kinds scopes the selector to specific Kubernetes `kind` values. Empty
+	// means it applies regardless of kind.
+	Kinds []string `yaml:"kinds,omitempty"`
+}
+
+// KindOverride customizes redaction for a specific Kubernetes `kind`.
+type KindOverride struct {
+	// DropFields are dotted field paths removed unconditionally for this
+	// kind (e.g. "data", "stringData" on a Secret).
+	DropFields []string `yaml:"dropFields"`
+	// KeepFields are dotted field paths exempted from KeyRules/ValueRules
+	// for this kind (e.g. "data" on a ConfigMap).
+	KeepFields []string `yaml:"keepFields"`
+}
+
+// LoadRedactionPolicy reads and parses a RedactionPolicy from a YAML file,
+// precompiling its ValueRules and regex KeyRules so a bad pattern is
+// reported here rather than silently matching nothing at scrub time.
+func LoadRedactionPolicy(path string) (RedactionPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return RedactionPolicy{}, fmt.Errorf("failed to read redaction policy %s: %w", path, err)
+	}
+	var policy RedactionPolicy
+	if err := yaml.Unmarshal(data, &policy); err != nil {
+		return RedactionPolicy{}, fmt.Errorf("failed to parse redaction policy %s: %w", path, err)
+	}
+	return policy.compile()
+}
+
+// compile precompiles every ValueRule's Pattern and every regex-mode
+// KeyRule's Pattern, returning a new RedactionPolicy with those regexes
+// attached for scrubString/keyMatches to reuse on every field instead of
+// recompiling per call. Returns an error naming the first invalid pattern.
+func (p RedactionPolicy) compile() (RedactionPolicy, error) {
+	valueRules := make([]ValueRule, len(p.ValueRules))
+	for i, vr := range p.ValueRules {
+		re, err := regexp.Compile(vr.Pattern)
+		if err != nil {
+			return RedactionPolicy{}, fmt.Errorf("invalid value rule pattern %q: %w", vr.Pattern, err)
+		}
+		vr.compiled = re
+		valueRules[i] = vr
+	}
+
+	keyRules := make([]KeyRule, len(p.KeyRules))
+	for i, kr := range p.KeyRules {
+		if kr.Match == KeyMatchRegex {
+			re, err := regexp.Compile(kr.Pattern)
+			if err != nil {
+				return RedactionPolicy{}, fmt.Errorf("invalid key rule pattern %q: %w", kr.Pattern, err)
+			}
+			kr.compiled = re
+		}
+		keyRules[i] = kr
+	}
+
+	p.ValueRules = valueRules
+	p.KeyRules = keyRules
+	return p, nil
+}
+
+// DefaultRedactionPolicy returns the built-in policy used by WithRedaction()
+// when no explicit policy is supplied. It masks common secret-shaped keys
+// and "key=value"/"key: value" text, and fully drops a Secret's
+// .data/.stringData -- matching the bundler's previous hardcoded behavior.
+func DefaultRedactionPolicy() RedactionPolicy {
+	policy := RedactionPolicy{
+		KeyRules: []KeyRule{
+			{Pattern: "password", Match: KeyMatchSubstring},
+			{Pattern: "token", Match: KeyMatchSubstring},
+			{Pattern: "key", Match: KeyMatchSubstring},
+			{Pattern: "secret", Match: KeyMatchSubstring},
+			{Pattern: "authorization", Match: KeyMatchSubstring},
+		},
+		ValueRules: []ValueRule{
+			{Pattern: `(?i)(password|token|key|secret)\s*[:=]\s*["']?([^"'\s]+)["']?`, MaskTemplate: "$1: [REDACTED]"},
+		},
+		KindOverrides: map[string]KindOverride{
+			"Secret": {DropFields: []string{"data", "stringData"}},
+		},
+	}
+
+	compiled, err := policy.compile()
+	if err != nil {
+		// The built-in patterns are a compile-time constant; a failure here
+		// means the constant itself is broken, not bad user input.
+		panic(fmt.Sprintf("bundler: default redaction policy failed to compile: %v", err))
+	}
+	return compiled
+}
@@ -2,118 +2,260 @@ package bundler
 
 import (
 	"encoding/json"
-	"regexp"
+	"fmt"
+	"path"
 	"strings"
-)
 
-// Redactor defines the interface for redaction rules.
-type Redactor interface {
-	Redact(input []byte) []byte
-	RedactString(input string) string
-}
+	"github.com/mrhapile/fluid-diagnose-bundler/pkg/types"
+)
 
-type regexRedactor struct {
-	patterns []*regexp.Regexp
-	mask     string
+// Scrub walks a value and applies the policy's KeyRules, ValueRules,
+// Selectors, and KindOverrides. It understands []byte and string (matched
+// only against ValueRules, since there's no field name to key off), and the
+// generic map[string]interface{}/[]interface{} shapes produced by decoding
+// JSON or YAML -- anything else (numbers, bools, nil, already-typed
+// structs) passes through unchanged. kind is the document's Kubernetes
+// `kind`, used to evaluate KindOverrides and kind-scoped Selectors ("" if
+// not applicable or unknown). If dryRun is true, the input is returned
+// unmodified and every match is instead recorded in the returned
+// DryRunReport.
+func (p RedactionPolicy) Scrub(data interface{}, kind string, dryRun bool) (interface{}, *types.DryRunReport) {
+	report := &types.DryRunReport{}
+	scrubbed := p.scrubValue(data, kind, "", dryRun, report)
+	return scrubbed, report
 }
 
-func newRedactor() Redactor {
-	// Common patterns for secrets and tokens
-	return &regexRedactor{
-		patterns: []*regexp.Regexp{
-			regexp.MustCompile(`(?i)(password|token|key|secret)\s*[:=]\s*["']?([^"'\s]+)["']?`),
-			// Basic IP address regex (IPv4)
-			// regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`), // Disabled by default, too aggressive
-		},
-		mask: "[REDACTED]",
+// ScrubDocument round-trips a Go value (typically a struct or a named map
+// type like types.ResourceGraph) through JSON to obtain a generic, walkable
+// document, then applies Scrub to it. The document's own top-level "kind"
+// field, if present, is used to evaluate KindOverrides and kind-scoped
+// Selectors.
+func (p RedactionPolicy) ScrubDocument(data interface{}, dryRun bool) (interface{}, *types.DryRunReport, error) {
+	buf, err := json.Marshal(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(buf, &generic); err != nil {
+		return nil, nil, err
 	}
-}
 
-func (r *regexRedactor) Redact(input []byte) []byte {
-	s := string(input)
-	for _, p := range r.patterns {
-		s = p.ReplaceAllString(s, "$1: "+r.mask)
+	kind := ""
+	if m, ok := generic.(map[string]interface{}); ok {
+		if k, ok := m["kind"].(string); ok {
+			kind = k
+		}
 	}
-	return []byte(s)
+
+	scrubbed, report := p.Scrub(generic, kind, dryRun)
+	return scrubbed, report, nil
 }
 
-func (r *regexRedactor) RedactString(input string) string {
-	for _, p := range r.patterns {
-		input = p.ReplaceAllString(input, "$1: "+r.mask)
+func (p RedactionPolicy) scrubValue(v interface{}, kind, fieldPath string, dryRun bool, report *types.DryRunReport) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return p.scrubMap(val, kind, fieldPath, dryRun, report)
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, elem := range val {
+			out[i] = p.scrubValue(elem, kind, fmt.Sprintf("%s[%d]", fieldPath, i), dryRun, report)
+		}
+		return out
+	case []byte:
+		return []byte(p.scrubString(string(val), fieldPath, dryRun, report))
+	case string:
+		return p.scrubString(val, fieldPath, dryRun, report)
+	default:
+		return v
 	}
-	return input
 }
 
-// scrubMap recursively scrubs sensitive keys from a map[string]interface{}.
-// This is more robust for structured JSON/YAML data than regex replacement.
-func scrubMap(data map[string]interface{}) map[string]interface{} {
-	out := make(map[string]interface{})
-	sensitiveKeys := []string{"password", "token", "key", "secret", "authorization"}
-
-	for k, v := range data {
-		isSensitive := false
-		lowerK := strings.ToLower(k)
-		for _, sk := range sensitiveKeys {
-			if strings.Contains(lowerK, sk) {
-				isSensitive = true
-				break
+func (p RedactionPolicy) scrubMap(m map[string]interface{}, kind, pathPrefix string, dryRun bool, report *types.DryRunReport) map[string]interface{} {
+	override, hasOverride := p.KindOverrides[kind]
+
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		fieldPath := k
+		if pathPrefix != "" {
+			fieldPath = pathPrefix + "." + k
+		}
+
+		if hasOverride && matchesAnyDottedSuffix(override.DropFields, fieldPath) {
+			report.Actions = append(report.Actions, types.RedactAction{Path: fieldPath, Reason: "kindOverride", Action: "drop"})
+			if dryRun {
+				out[k] = v
+			}
+			continue
+		}
+
+		if action, ok := p.matchSelector(fieldPath, kind); ok {
+			if action == SelectorDrop {
+				report.Actions = append(report.Actions, types.RedactAction{Path: fieldPath, Reason: "selector", Action: "drop"})
+				if dryRun {
+					out[k] = v
+				}
+				continue
 			}
+			// SelectorKeep: skip key/value rules for this field, but still recurse.
+			kept := p
+			kept.KeyRules, kept.ValueRules = nil, nil
+			out[k] = kept.scrubValue(v, kind, fieldPath, dryRun, report)
+			continue
 		}
 
-		if isSensitive {
-			out[k] = "[REDACTED]"
+		if hasOverride && matchesAnyDottedSuffix(override.KeepFields, fieldPath) {
+			kept := p
+			kept.KeyRules, kept.ValueRules = nil, nil
+			out[k] = kept.scrubValue(v, kind, fieldPath, dryRun, report)
 			continue
 		}
 
-		switch val := v.(type) {
-		case map[string]interface{}:
-			out[k] = scrubMap(val)
-		case []interface{}:
-			out[k] = scrubSlice(val)
-		default:
-			out[k] = v
+		if p.matchesKeyRule(k) {
+			report.Actions = append(report.Actions, types.RedactAction{Path: fieldPath, Reason: "keyRule", Action: "redact"})
+			if dryRun {
+				out[k] = v
+			} else {
+				out[k] = "[REDACTED]"
+			}
+			continue
 		}
+
+		out[k] = p.scrubValue(v, kind, fieldPath, dryRun, report)
 	}
 	return out
 }
 
-func scrubSlice(data []interface{}) []interface{} {
-	out := make([]interface{}, len(data))
-	for i, v := range data {
-		switch val := v.(type) {
-		case map[string]interface{}:
-			out[i] = scrubMap(val)
-		case []interface{}:
-			out[i] = scrubSlice(val)
-		default:
-			out[i] = v
+func (p RedactionPolicy) scrubString(s, fieldPath string, dryRun bool, report *types.DryRunReport) string {
+	for _, vr := range p.ValueRules {
+		// compiled is set by RedactionPolicy.compile, which every
+		// constructor (LoadRedactionPolicy, DefaultRedactionPolicy,
+		// WithRedactionRules) routes through; nil here means the policy
+		// bypassed those and is missing a pattern, so skip it rather than
+		// matching spuriously.
+		if vr.compiled == nil {
+			continue
+		}
+		if !vr.compiled.MatchString(s) {
+			continue
+		}
+
+		report.Actions = append(report.Actions, types.RedactAction{Path: fieldPath, Reason: "valueRule", Action: "redact"})
+		if dryRun {
+			return s
 		}
+		if vr.MaskTemplate != "" {
+			return vr.compiled.ReplaceAllString(s, vr.MaskTemplate)
+		}
+		return vr.compiled.ReplaceAllString(s, "[REDACTED]")
 	}
-	return out
+	return s
 }
 
-// scrubJSON wraps the map redaction for generic input.
-func scrubJSON(input interface{}) (interface{}, error) {
-	// Round-trip to scrub if needed, or implement direct traversal
-	// For simplicity, we assume input is already a map or slice if we want deep scrubbing.
-	// If it's a struct, we should marshal it first then unmarshal to map to scrub generically without reflection complexity.
-	var buf []byte
-	var err error
-	buf, err = json.Marshal(input)
-	if err != nil {
-		return nil, err
+func (p RedactionPolicy) matchesKeyRule(key string) bool {
+	for _, rule := range p.KeyRules {
+		if keyMatches(rule, key) {
+			return true
+		}
 	}
+	return false
+}
 
-	var data interface{}
-	if err := json.Unmarshal(buf, &data); err != nil {
-		return nil, err
+func keyMatches(rule KeyRule, key string) bool {
+	switch rule.Match {
+	case KeyMatchExact:
+		return key == rule.Pattern
+	case KeyMatchGlob:
+		ok, err := path.Match(rule.Pattern, key)
+		return err == nil && ok
+	case KeyMatchRegex:
+		return rule.compiled != nil && rule.compiled.MatchString(key)
+	case KeyMatchSubstring, "":
+		return strings.Contains(strings.ToLower(key), strings.ToLower(rule.Pattern))
+	default:
+		return false
 	}
+}
 
-	switch v := data.(type) {
-	case map[string]interface{}:
-		return scrubMap(v), nil
-	case []interface{}:
-		return scrubSlice(v), nil
+// matchSelector evaluates p.Selectors against fieldPath (dotted, with
+// "[N]" array indices) for the given kind, returning the first matching
+// selector's action.
+func (p RedactionPolicy) matchSelector(fieldPath, kind string) (SelectorAction, bool) {
+	for _, sel := range p.Selectors {
+		if len(sel.Kinds) > 0 && !containsString(sel.Kinds, kind) {
+			continue
+		}
+		if selectorMatches(sel.Path, fieldPath) {
+			return sel.Action, true
+		}
+	}
+	return "", false
+}
+
+// selectorMatches compares a JSONPath-lite selector (e.g.
+// "$.spec.runtime.tieredstore.levels[*].mediumtype" or "$.data.*") against a
+// dotted field path (e.g. "spec.runtime.tieredstore.levels[0].mediumtype").
+// "[*]" matches any array index, and a "*" segment matches any single key.
+func selectorMatches(selector, fieldPath string) bool {
+	selector = strings.TrimPrefix(selector, "$.")
+	selector = strings.TrimPrefix(selector, "$")
+
+	selSegs := splitFieldPath(selector)
+	fieldSegs := splitFieldPath(fieldPath)
+	if len(selSegs) != len(fieldSegs) {
+		return false
+	}
+	for i, seg := range selSegs {
+		if seg == "*" || seg == "[*]" {
+			continue
+		}
+		if seg != fieldSegs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// splitFieldPath splits a dotted path with optional "[N]"/"[*]" index
+// suffixes into its individual segments, keeping each index as its own
+// segment (e.g. "levels[0].mediumtype" -> ["levels", "[0]", "mediumtype"]).
+func splitFieldPath(p string) []string {
+	var segs []string
+	for _, dotSeg := range strings.Split(p, ".") {
+		for dotSeg != "" {
+			idx := strings.IndexByte(dotSeg, '[')
+			if idx < 0 {
+				segs = append(segs, dotSeg)
+				break
+			}
+			if idx > 0 {
+				segs = append(segs, dotSeg[:idx])
+			}
+			end := strings.IndexByte(dotSeg[idx:], ']')
+			if end < 0 {
+				segs = append(segs, dotSeg)
+				break
+			}
+			segs = append(segs, dotSeg[idx:idx+end+1])
+			dotSeg = dotSeg[idx+end+1:]
+		}
+	}
+	return segs
+}
+
+func matchesAnyDottedSuffix(paths []string, fieldPath string) bool {
+	for _, p := range paths {
+		if fieldPath == p || strings.HasSuffix(fieldPath, "."+p) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
 	}
-	return data, nil
+	return false
 }
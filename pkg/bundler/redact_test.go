@@ -0,0 +1,138 @@
+package bundler_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mrhapile/fluid-diagnose-bundler/pkg/bundler"
+	"github.com/mrhapile/fluid-diagnose-bundler/pkg/bundler/verify"
+	"github.com/mrhapile/fluid-diagnose-bundler/pkg/types"
+)
+
+func TestBuildWithRedactionPolicyDropsSecretData(t *testing.T) {
+	outDir, err := os.MkdirTemp("", "fluid-test-redact")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outDir)
+
+	input := types.BundleInput{
+		Graph: types.ResourceGraph{
+			"kind": "Secret",
+			"data": map[string]interface{}{
+				"accessKey": "AKIAEXAMPLE",
+			},
+		},
+		Diagnosis: types.DiagnosticResult{Issues: []types.Issue{}},
+		Metadata:  types.BundleMetadata{Environment: "test"},
+	}
+
+	result, err := bundler.Build(input,
+		bundler.WithTimestamp(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)),
+		bundler.WithOutputDir(outDir),
+		bundler.WithRedactionRules(bundler.DefaultRedactionPolicy()),
+	)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if result.RedactionReport == nil || len(result.RedactionReport.Actions) == 0 {
+		t.Fatal("expected a non-empty redaction report")
+	}
+
+	foundDrop := false
+	for _, a := range result.RedactionReport.Actions {
+		if a.Path == "data" && a.Action == "drop" {
+			foundDrop = true
+		}
+	}
+	if !foundDrop {
+		t.Errorf("expected Secret.data to be dropped, got actions: %+v", result.RedactionReport.Actions)
+	}
+}
+
+func TestBuildWithRedactionDryRunLeavesContentUntouched(t *testing.T) {
+	outDir, err := os.MkdirTemp("", "fluid-test-redact-dryrun")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outDir)
+
+	input := types.BundleInput{
+		Graph:     types.ResourceGraph{"kind": "Dataset"},
+		Diagnosis: types.DiagnosticResult{Issues: []types.Issue{}},
+		Metadata:  types.BundleMetadata{Environment: "test"},
+		Logs: map[string][]byte{
+			"test.log": []byte("password=supersecret\n"),
+		},
+	}
+
+	result, err := bundler.Build(input,
+		bundler.WithTimestamp(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)),
+		bundler.WithOutputDir(outDir),
+		bundler.WithRedaction(),
+		bundler.WithRedactionDryRun(),
+	)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if result.RedactionReport == nil || len(result.RedactionReport.Actions) == 0 {
+		t.Fatal("expected dry-run report to record the password match")
+	}
+
+	b, err := verify.Open(result.ArchivePath)
+	if err != nil {
+		t.Fatalf("verify.Open failed: %v", err)
+	}
+	defer b.Close()
+
+	r, err := b.File(filepath.Join(bundler.LogsDir, "test.log"))
+	if err != nil {
+		t.Fatalf("File failed: %v", err)
+	}
+	content, err := io.ReadAll(r)
+	r.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != string(input.Logs["test.log"]) {
+		t.Errorf("expected dry run to leave log content untouched, got %q", content)
+	}
+}
+
+func TestLoadRedactionPolicyFromYAML(t *testing.T) {
+	dir, err := os.MkdirTemp("", "fluid-test-policy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	policyPath := filepath.Join(dir, "policy.yaml")
+	yaml := `
+keyRules:
+  - pattern: "accessKeyId"
+    match: exact
+kindOverrides:
+  Secret:
+    dropFields:
+      - data
+`
+	if err := os.WriteFile(policyPath, []byte(yaml), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	policy, err := bundler.LoadRedactionPolicy(policyPath)
+	if err != nil {
+		t.Fatalf("LoadRedactionPolicy failed: %v", err)
+	}
+	if len(policy.KeyRules) != 1 || policy.KeyRules[0].Pattern != "accessKeyId" {
+		t.Errorf("unexpected key rules: %+v", policy.KeyRules)
+	}
+	if _, ok := policy.KindOverrides["Secret"]; !ok {
+		t.Error("expected a Secret kind override")
+	}
+}
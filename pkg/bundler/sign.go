@@ -0,0 +1,118 @@
+package bundler
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Signer signs a manifest's ContentHash so a downstream reader can prove a
+// bundle hasn't been tampered with between generation and analysis. digest
+// is the raw bytes of the decoded ContentHash (not its hex string).
+type Signer interface {
+	Sign(digest []byte) (sig []byte, keyID string, err error)
+}
+
+// Ed25519Signer is a built-in Signer backed by a long-lived ed25519 key
+// pair, identified to verifiers by keyID.
+type Ed25519Signer struct {
+	key   ed25519.PrivateKey
+	keyID string
+}
+
+// NewEd25519Signer wraps an ed25519 private key as a Signer.
+func NewEd25519Signer(key ed25519.PrivateKey, keyID string) *Ed25519Signer {
+	return &Ed25519Signer{key: key, keyID: keyID}
+}
+
+// Sign signs digest directly; ed25519 has no separate prehashing mode.
+func (s *Ed25519Signer) Sign(digest []byte) ([]byte, string, error) {
+	return ed25519.Sign(s.key, digest), s.keyID, nil
+}
+
+// KeylessSigner is a cosign-style signer stub: instead of a long-lived key,
+// it pairs an ephemeral key with a short-lived identity certificate obtained
+// out of band (e.g. from Fulcio via an OIDC token). CertChain is expected to
+// be populated with that certificate (PEM-encoded) before Sign is called;
+// verifiers identify a keyless signature by its certificate chain rather
+// than a stable keyID.
+type KeylessSigner struct {
+	key       ed25519.PrivateKey
+	CertChain []byte
+}
+
+// NewKeylessSigner generates the ephemeral key pair for one-shot,
+// certificate-backed signing. Callers are expected to populate CertChain
+// (e.g. from a Fulcio-issued certificate) before calling Sign.
+func NewKeylessSigner() (*KeylessSigner, error) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate ephemeral signing key: %w", err)
+	}
+	return &KeylessSigner{key: priv}, nil
+}
+
+// Sign signs digest with the ephemeral key.
+func (s *KeylessSigner) Sign(digest []byte) ([]byte, string, error) {
+	return ed25519.Sign(s.key, digest), "", nil
+}
+
+// SignatureFile is the detached signature blob written alongside the
+// archive, cosign-style.
+const SignatureFile = "manifest.sig"
+
+// SignaturesIndexFile maps "sha256:<manifestDigest>" to the SignatureEntry
+// that signs it.
+const SignaturesIndexFile = "signatures.json"
+
+// SignatureEntry is one entry of signatures.json.
+type SignatureEntry struct {
+	Signature string `json:"signature"`           // base64-encoded
+	KeyID     string `json:"keyId,omitempty"`     // set for key-based Signers
+	CertChain string `json:"certChain,omitempty"` // base64-encoded PEM chain, keyless signing only
+}
+
+// signManifest signs contentHash (the manifest's hex-encoded ContentHash)
+// with signer and writes SignatureFile and SignaturesIndexFile into
+// outputDir alongside the archive.
+func signManifest(outputDir, contentHash string, signer Signer) error {
+	digest, err := hex.DecodeString(contentHash)
+	if err != nil {
+		return fmt.Errorf("invalid manifest content hash: %w", err)
+	}
+
+	sig, keyID, err := signer.Sign(digest)
+	if err != nil {
+		return fmt.Errorf("failed to sign manifest: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(outputDir, SignatureFile), sig, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", SignatureFile, err)
+	}
+
+	entry := SignatureEntry{
+		Signature: base64.StdEncoding.EncodeToString(sig),
+		KeyID:     keyID,
+	}
+	if ks, ok := signer.(*KeylessSigner); ok && len(ks.CertChain) > 0 {
+		entry.CertChain = base64.StdEncoding.EncodeToString(ks.CertChain)
+	}
+
+	index := map[string]SignatureEntry{
+		"sha256:" + contentHash: entry,
+	}
+	indexBytes, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", SignaturesIndexFile, err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, SignaturesIndexFile), indexBytes, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", SignaturesIndexFile, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,243 @@
+// Package verify reads back a bundle produced by pkg/bundler and checks it
+// against the promises its manifest makes: that every file's SHA256 matches
+// what's recorded, and that the manifest's own ContentHash is consistent
+// with those per-file hashes.
+package verify
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/mrhapile/fluid-diagnose-bundler/pkg/bundler"
+	"github.com/mrhapile/fluid-diagnose-bundler/pkg/types"
+)
+
+// FileStatus reports the verification outcome for a single manifest entry.
+type FileStatus struct {
+	Path     string
+	Expected string
+	Actual   string
+	OK       bool
+	Err      string
+}
+
+// VerifyReport is the aggregate result of verifying a bundle against its manifest.
+type VerifyReport struct {
+	Files       []FileStatus
+	ContentHash struct {
+		Expected string
+		Actual   string
+		OK       bool
+	}
+	OK bool
+}
+
+// Verify opens the archive at path, recomputes every file's SHA256 and
+// compares it against manifest.json's FileEntry.SHA256, then recomputes
+// ContentHash using bundler.ComputeContentHash over those freshly-read
+// hashes (not the manifest's declared ones) and compares that too, so a
+// file whose content was swapped can't also carry a forged SHA256 entry.
+func Verify(path string) (*VerifyReport, error) {
+	b, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	report := &VerifyReport{OK: true}
+
+	files := append([]types.FileEntry(nil), b.manifest.Files...)
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+
+	actual := make([]types.FileEntry, 0, len(files))
+	for _, entry := range files {
+		status := FileStatus{Path: entry.Path, Expected: entry.SHA256}
+
+		r, err := b.File(entry.Path)
+		if err != nil {
+			status.Err = err.Error()
+			report.OK = false
+			report.Files = append(report.Files, status)
+			continue
+		}
+
+		fileHasher := sha256.New()
+		_, copyErr := io.Copy(fileHasher, r)
+		r.Close()
+		if copyErr != nil {
+			status.Err = copyErr.Error()
+			report.OK = false
+			report.Files = append(report.Files, status)
+			continue
+		}
+
+		status.Actual = hex.EncodeToString(fileHasher.Sum(nil))
+		status.OK = status.Actual == status.Expected
+		if !status.OK {
+			report.OK = false
+		}
+		report.Files = append(report.Files, status)
+
+		actual = append(actual, types.FileEntry{Path: entry.Path, SHA256: status.Actual})
+	}
+
+	report.ContentHash.Expected = b.manifest.ContentHash
+	report.ContentHash.Actual = bundler.ComputeContentHash(actual)
+	report.ContentHash.OK = report.ContentHash.Actual == report.ContentHash.Expected
+	if !report.ContentHash.OK {
+		report.OK = false
+	}
+
+	return report, nil
+}
+
+// Bundle is an indexed, read-only accessor over a produced bundle archive.
+type Bundle struct {
+	manifest types.BundleManifest
+	entries  map[string][]byte
+	dir      string
+}
+
+// Open reads the archive at path and returns an indexed accessor over its
+// entries and parsed manifest. The archive's compression (gzip, zstd, or
+// none) is inferred from its file extension.
+func Open(path string) (*Bundle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+
+	tr, closeSrc, err := newTarReader(path, f)
+	if err != nil {
+		return nil, err
+	}
+	if closeSrc != nil {
+		defer closeSrc()
+	}
+
+	entries := make(map[string][]byte)
+	var rootPrefix string
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tar entry: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+
+		// Strip the bundle's base directory prefix (e.g.
+		// "fluid-diagnose-demo-20240101-120000/") so callers address files by
+		// their logical archive-relative path.
+		name := header.Name
+		if rootPrefix == "" {
+			if idx := strings.IndexByte(name, '/'); idx >= 0 {
+				rootPrefix = name[:idx+1]
+			}
+		}
+		entries[strings.TrimPrefix(name, rootPrefix)] = content
+	}
+
+	manifestBytes, ok := entries["manifest.json"]
+	if !ok {
+		return nil, fmt.Errorf("manifest.json not found in archive")
+	}
+	var manifest types.BundleManifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest.json: %w", err)
+	}
+
+	return &Bundle{manifest: manifest, entries: entries, dir: filepath.Dir(path)}, nil
+}
+
+// Manifest returns the bundle's parsed manifest.
+func (b *Bundle) Manifest() types.BundleManifest {
+	return b.manifest
+}
+
+// File returns a reader for the named file inside the bundle, addressed
+// relative to the archive root (e.g. "logs/foo.log").
+func (b *Bundle) File(path string) (io.ReadCloser, error) {
+	content, ok := b.entries[path]
+	if !ok {
+		return nil, fmt.Errorf("file not found in bundle: %s", path)
+	}
+	return io.NopCloser(bytes.NewReader(content)), nil
+}
+
+// VerifySignature checks the detached manifest.sig written alongside the
+// archive (see bundler.WithSigning) against the bundle's ContentHash, using
+// pub to verify. Only ed25519 public keys are currently supported, matching
+// the built-in bundler.Ed25519Signer and bundler.KeylessSigner.
+func (b *Bundle) VerifySignature(pub crypto.PublicKey) error {
+	edPub, ok := pub.(ed25519.PublicKey)
+	if !ok {
+		return fmt.Errorf("unsupported public key type %T", pub)
+	}
+
+	sig, err := os.ReadFile(filepath.Join(b.dir, bundler.SignatureFile))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", bundler.SignatureFile, err)
+	}
+
+	digest, err := hex.DecodeString(b.manifest.ContentHash)
+	if err != nil {
+		return fmt.Errorf("invalid manifest content hash: %w", err)
+	}
+
+	if !ed25519.Verify(edPub, digest, sig) {
+		return fmt.Errorf("signature verification failed for %s", bundler.SignatureFile)
+	}
+	return nil
+}
+
+// Close releases resources held by the bundle. It is currently a no-op
+// since Open reads the archive fully into memory, but is kept for API
+// stability as bundle reading grows lazier.
+func (b *Bundle) Close() error { return nil }
+
+// newTarReader returns a tar.Reader over f appropriate for path's
+// compression, plus an optional close func for the decompressor (nil if
+// none is needed).
+func newTarReader(path string, f io.Reader) (*tar.Reader, func() error, error) {
+	switch {
+	case strings.HasSuffix(path, ".tar.gz"), strings.HasSuffix(path, ".tgz"):
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open gzip reader: %w", err)
+		}
+		return tar.NewReader(gz), gz.Close, nil
+	case strings.HasSuffix(path, ".tar.zst"):
+		zr, err := zstd.NewReader(f)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open zstd reader: %w", err)
+		}
+		return tar.NewReader(zr.IOReadCloser()), func() error { zr.Close(); return nil }, nil
+	case strings.HasSuffix(path, ".tar"):
+		return tar.NewReader(f), nil, nil
+	default:
+		return nil, nil, fmt.Errorf("unrecognized archive extension: %s", path)
+	}
+}
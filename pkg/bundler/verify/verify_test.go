@@ -0,0 +1,101 @@
+package verify_test
+
+import (
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mrhapile/fluid-diagnose-bundler/pkg/bundler"
+	"github.com/mrhapile/fluid-diagnose-bundler/pkg/bundler/verify"
+	"github.com/mrhapile/fluid-diagnose-bundler/pkg/types"
+)
+
+func TestVerifyRoundTrip(t *testing.T) {
+	outDir, err := os.MkdirTemp("", "fluid-test-verify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outDir)
+
+	input := types.BundleInput{
+		Graph:     types.ResourceGraph{"kind": "Dataset"},
+		Diagnosis: types.DiagnosticResult{Issues: []types.Issue{}},
+		Metadata:  types.BundleMetadata{Environment: "test"},
+		Logs: map[string][]byte{
+			"test.log": []byte("test content"),
+		},
+		Resources: map[string]string{
+			"dataset.yaml": "kind: Dataset\n",
+		},
+	}
+
+	result, err := bundler.Build(input,
+		bundler.WithTimestamp(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)),
+		bundler.WithOutputDir(outDir),
+	)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	report, err := verify.Verify(result.ArchivePath)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if !report.OK {
+		t.Errorf("report not OK: %+v", report)
+	}
+	if !report.ContentHash.OK {
+		t.Errorf("content hash mismatch: expected %s, got %s", report.ContentHash.Expected, report.ContentHash.Actual)
+	}
+
+	b, err := verify.Open(result.ArchivePath)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer b.Close()
+
+	r, err := b.File("logs/test.log")
+	if err != nil {
+		t.Fatalf("File failed: %v", err)
+	}
+	defer r.Close()
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if string(content) != "test content" {
+		t.Errorf("unexpected content: %q", content)
+	}
+}
+
+func TestVerifyCleanBundleWithNoLogsOrResources(t *testing.T) {
+	outDir, err := os.MkdirTemp("", "fluid-test-verify-minimal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outDir)
+
+	input := types.BundleInput{
+		Graph:     types.ResourceGraph{"kind": "Dataset"},
+		Diagnosis: types.DiagnosticResult{Issues: []types.Issue{}},
+		Metadata:  types.BundleMetadata{Environment: "test"},
+	}
+
+	result, err := bundler.Build(input,
+		bundler.WithTimestamp(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)),
+		bundler.WithOutputDir(outDir),
+	)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	report, err := verify.Verify(result.ArchivePath)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if !report.OK {
+		t.Fatalf("expected a freshly built bundle to verify clean: %+v", report)
+	}
+}
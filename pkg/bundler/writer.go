@@ -2,45 +2,139 @@ package bundler
 
 import (
 	"archive/tar"
+	"bytes"
 	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
 	"time"
+
+	"github.com/klauspost/compress/zstd"
+	pgzip "github.com/klauspost/pgzip"
+	"github.com/mrhapile/fluid-diagnose-bundler/pkg/types"
+)
+
+// Compression selects the algorithm used to compress the archive.
+type Compression string
+
+const (
+	CompressionGzip Compression = "gzip"
+	CompressionZstd Compression = "zstd"
+	CompressionNone Compression = "none"
 )
 
-// ArchiveWriter handles the creation of the .tar.gz file with deterministic ordering.
+// parallelGzipThreshold is the largest-entry size above which a gzip archive
+// switches to the parallel pgzip implementation.
+const parallelGzipThreshold = 8 * 1024 * 1024 // 8MiB
+
+// Source lets large files (fuse logs, resource dumps) stream directly into
+// the archive instead of being buffered in memory up front. It is an alias
+// of types.Source so BundleInput can reference the same contract.
+type Source = types.Source
+
+// byteSource adapts an in-memory []byte to Source, used internally for the
+// many small, already-serialized files (graph.json, manifest.json, etc).
+type byteSource struct {
+	data []byte
+}
+
+func (s byteSource) Open() (io.ReadCloser, int64, error) {
+	return io.NopCloser(bytes.NewReader(s.data)), int64(len(s.data)), nil
+}
+
+// FileDigest reports the size and content hash of a file as it was streamed
+// into the archive, computed on the fly so callers never need to hold the
+// full content in memory.
+type FileDigest struct {
+	Path   string
+	Size   int64
+	SHA256 string
+}
+
+// ArchiveWriter handles the creation of the archive file with deterministic
+// ordering. Entries are streamed from their Source at write time, so large
+// entries never need to be buffered in full.
 type ArchiveWriter struct {
-	baseDir string // The root directory name inside the archive (e.g. fluid-diagnose-xyz)
-	files   map[string][]byte
-	ts      time.Time
+	baseDir     string // The root directory name inside the archive (e.g. fluid-diagnose-xyz)
+	sources     map[string]Source
+	ts          time.Time
+	compression Compression
+
+	// baseline, when set via SetBaseline, maps path -> sha256 from a prior
+	// bundle's manifest.json. Entries whose content still matches it are
+	// written as zero-byte placeholders instead of being repacked.
+	baseline map[string]string
+	// deltaEntries records every entry's status relative to baseline,
+	// populated by WriteToDisk. Empty unless SetBaseline was called.
+	deltaEntries []types.DeltaEntry
 }
 
-// NewArchiveWriter creates a new writer instance.
+// NewArchiveWriter creates a new writer instance using gzip compression.
 func NewArchiveWriter(baseDir string, ts time.Time) *ArchiveWriter {
 	return &ArchiveWriter{
-		baseDir: baseDir,
-		files:   make(map[string][]byte),
-		ts:      ts,
+		baseDir:     baseDir,
+		sources:     make(map[string]Source),
+		ts:          ts,
+		compression: CompressionGzip,
+	}
+}
+
+// SetCompression selects the compression algorithm used by WriteToDisk.
+func (w *ArchiveWriter) SetCompression(c Compression) {
+	w.compression = c
+}
+
+// SetBaseline configures the (path, sha256) state of a prior bundle to diff
+// against. Once set, WriteToDisk replaces any unchanged entry's content with
+// a zero-byte placeholder and appends delta.json recording every entry's
+// status, so pkg/bundler/merge can later reconstitute a full bundle from
+// baseline + delta.
+func (w *ArchiveWriter) SetBaseline(files []types.FileEntry) {
+	w.baseline = make(map[string]string, len(files))
+	for _, f := range files {
+		w.baseline[f.Path] = f.SHA256
 	}
 }
 
-// AddFile adds a file to be included in the archive.
+// DeltaEntries returns each archived path's status relative to the baseline
+// configured via SetBaseline, populated once WriteToDisk has run. Empty if
+// no baseline was configured.
+func (w *ArchiveWriter) DeltaEntries() []types.DeltaEntry {
+	return w.deltaEntries
+}
+
+// AddFile adds an in-memory file to be included in the archive.
 // path should be relative to the archive root (e.g. "manifest.json", "logs/foo.log").
 func (w *ArchiveWriter) AddFile(path string, content []byte) {
-	w.files[path] = content
+	w.sources[path] = byteSource{data: content}
+}
+
+// AddSource registers a file whose content is streamed from src at write
+// time instead of being buffered in memory ahead of time. Intended for large
+// log files and resource dumps that would otherwise OOM the builder.
+func (w *ArchiveWriter) AddSource(path string, src Source) {
+	w.sources[path] = src
 }
 
-// WriteToDisk creates the .tar.gz file at the specified output directory.
-// It returns the absolute path to the created file and the total uncompressed size.
-func (w *ArchiveWriter) WriteToDisk(outputDir string) (string, int64, error) {
+// WriteToDisk streams every registered source into a compressed tar archive
+// under outputDir, in sorted path order, hashing each entry on the fly via
+// an io.TeeReader. Once every entry has been written, buildManifest is
+// called with the resulting digests so the caller can produce a
+// manifest.json that is itself appended after every registered entry
+// (followed only by delta.json, when a baseline is set) before the archive
+// is closed. It returns the absolute archive path and the total
+// uncompressed size, including the manifest.
+func (w *ArchiveWriter) WriteToDisk(outputDir string, buildManifest func([]FileDigest) ([]byte, error)) (string, int64, error) {
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
 		return "", 0, fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	filename := fmt.Sprintf("%s.tar.gz", w.baseDir)
-	archivePath := filepath.Join(outputDir, filename)
+	archivePath := filepath.Join(outputDir, w.filename())
 	absPath, err := filepath.Abs(archivePath)
 	if err != nil {
 		return "", 0, fmt.Errorf("failed to resolve absolute path: %w", err)
@@ -52,45 +146,247 @@ func (w *ArchiveWriter) WriteToDisk(outputDir string) (string, int64, error) {
 	}
 	defer f.Close()
 
-	// Use gzip compression
-	gw := gzip.NewWriter(f)
-	defer gw.Close()
+	maxEntrySize, err := w.peekMaxSize()
+	if err != nil {
+		return "", 0, err
+	}
 
-	// Use tar writer
-	tw := tar.NewWriter(gw)
-	defer tw.Close()
+	cw, err := w.newCompressor(f, maxEntrySize)
+	if err != nil {
+		return "", 0, err
+	}
+	tw := tar.NewWriter(cw)
 
-	// Sort files by path for deterministic output
-	paths := make([]string, 0, len(w.files))
-	for p := range w.files {
+	paths := make([]string, 0, len(w.sources))
+	for p := range w.sources {
 		paths = append(paths, p)
 	}
 	sort.Strings(paths)
 
 	var totalSize int64
+	digests := make([]FileDigest, 0, len(paths))
 	for _, p := range paths {
-		content := w.files[p]
-		fullPath := filepath.Join(w.baseDir, p)
-
-		header := &tar.Header{
-			Name:       fullPath,
-			Size:       int64(len(content)),
-			Mode:       0644,
-			ModTime:    w.ts,
-			AccessTime: w.ts,
-			ChangeTime: w.ts,
-			Typeflag:   tar.TypeReg,
+		digest, delta, err := w.writeEntry(tw, p, w.sources[p])
+		if err != nil {
+			return "", 0, err
+		}
+		totalSize += digest.Size
+		digests = append(digests, digest)
+		if w.baseline != nil {
+			w.deltaEntries = append(w.deltaEntries, delta)
 		}
+	}
 
-		if err := tw.WriteHeader(header); err != nil {
-			return "", 0, fmt.Errorf("failed to write header for %s: %w", p, err)
+	manifestBytes, err := buildManifest(digests)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to build manifest: %w", err)
+	}
+	manifestDigest, _, err := w.writeEntry(tw, ManifestFile, byteSource{data: manifestBytes})
+	if err != nil {
+		return "", 0, err
+	}
+	totalSize += manifestDigest.Size
+
+	if w.baseline != nil {
+		for p, refSha := range w.baseline {
+			if _, ok := w.sources[p]; ok {
+				continue
+			}
+			w.deltaEntries = append(w.deltaEntries, types.DeltaEntry{Path: p, Status: types.DeltaRemoved, RefSHA256: refSha})
 		}
+		sort.Slice(w.deltaEntries, func(i, j int) bool { return w.deltaEntries[i].Path < w.deltaEntries[j].Path })
 
-		if _, err := tw.Write(content); err != nil {
-			return "", 0, fmt.Errorf("failed to write content for %s: %w", p, err)
+		deltaBytes, err := json.MarshalIndent(w.deltaEntries, "", "  ")
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to marshal %s: %w", DeltaFile, err)
+		}
+		deltaDigest, _, err := w.writeEntry(tw, DeltaFile, byteSource{data: deltaBytes})
+		if err != nil {
+			return "", 0, err
 		}
-		totalSize += int64(len(content))
+		totalSize += deltaDigest.Size
+	}
+
+	if err := tw.Close(); err != nil {
+		return "", 0, fmt.Errorf("failed to close tar writer: %w", err)
+	}
+	if err := cw.Close(); err != nil {
+		return "", 0, fmt.Errorf("failed to close compressor: %w", err)
 	}
 
 	return absPath, totalSize, nil
 }
+
+// writeEntry writes path's content into tw and returns its digest. When no
+// baseline is configured it streams src in a single pass, hashing via an
+// io.TeeReader so content never needs to be held in memory in full. When a
+// baseline is configured, it also returns a DeltaEntry recording path's
+// status relative to it; unchanged content is written as a zero-byte
+// placeholder instead of being repacked.
+func (w *ArchiveWriter) writeEntry(tw *tar.Writer, path string, src Source) (FileDigest, types.DeltaEntry, error) {
+	if w.baseline == nil {
+		digest, err := w.writeStreamedEntry(tw, path, src)
+		return digest, types.DeltaEntry{}, err
+	}
+	return w.writeEntryAgainstBaseline(tw, path, src)
+}
+
+// writeStreamedEntry is the single-pass path used when no baseline is
+// configured: src is opened once and copied straight into tw while being
+// hashed alongside via an io.TeeReader.
+func (w *ArchiveWriter) writeStreamedEntry(tw *tar.Writer, path string, src Source) (FileDigest, error) {
+	r, size, err := src.Open()
+	if err != nil {
+		return FileDigest{}, fmt.Errorf("failed to open source for %s: %w", path, err)
+	}
+	defer r.Close()
+
+	if err := w.writeHeader(tw, path, size); err != nil {
+		return FileDigest{}, err
+	}
+
+	hasher := sha256.New()
+	if _, err := io.Copy(tw, io.TeeReader(r, hasher)); err != nil {
+		return FileDigest{}, fmt.Errorf("failed to write content for %s: %w", path, err)
+	}
+
+	return FileDigest{
+		Path:   path,
+		Size:   size,
+		SHA256: hex.EncodeToString(hasher.Sum(nil)),
+	}, nil
+}
+
+// emptySHA256Hex is the SHA256 of zero bytes, i.e. the digest of the
+// zero-byte placeholder writeEntryAgainstBaseline writes for unchanged
+// entries.
+var emptySHA256Hex = hex.EncodeToString(sha256.New().Sum(nil))
+
+// writeEntryAgainstBaseline hashes src up front (without writing anything)
+// so it can tell whether path is unchanged relative to w.baseline. Unchanged
+// entries are written as a zero-byte placeholder, and the FileDigest
+// returned for the manifest reflects that placeholder's actual (empty)
+// content rather than src's real content, so a later verify.Verify of the
+// incremental archive alone checks what's actually on disk instead of
+// flagging every unchanged file as tampered; src's real hash is preserved
+// in the returned DeltaEntry.RefSHA256 for merge to restore it from the
+// baseline. Everything else is re-opened and written in full, same as
+// writeStreamedEntry.
+func (w *ArchiveWriter) writeEntryAgainstBaseline(tw *tar.Writer, path string, src Source) (FileDigest, types.DeltaEntry, error) {
+	r, size, err := src.Open()
+	if err != nil {
+		return FileDigest{}, types.DeltaEntry{}, fmt.Errorf("failed to open source for %s: %w", path, err)
+	}
+	hasher := sha256.New()
+	_, hashErr := io.Copy(hasher, r)
+	r.Close()
+	if hashErr != nil {
+		return FileDigest{}, types.DeltaEntry{}, fmt.Errorf("failed to hash content for %s: %w", path, hashErr)
+	}
+	sha256Hex := hex.EncodeToString(hasher.Sum(nil))
+
+	refSha, known := w.baseline[path]
+	if known && refSha == sha256Hex {
+		if err := w.writeHeader(tw, path, 0); err != nil {
+			return FileDigest{}, types.DeltaEntry{}, err
+		}
+		placeholder := FileDigest{Path: path, Size: 0, SHA256: emptySHA256Hex}
+		return placeholder, types.DeltaEntry{Path: path, Status: types.DeltaUnchanged, RefSHA256: refSha}, nil
+	}
+
+	digest := FileDigest{Path: path, Size: size, SHA256: sha256Hex}
+	r2, _, err := src.Open()
+	if err != nil {
+		return FileDigest{}, types.DeltaEntry{}, fmt.Errorf("failed to reopen source for %s: %w", path, err)
+	}
+	defer r2.Close()
+	if err := w.writeHeader(tw, path, size); err != nil {
+		return FileDigest{}, types.DeltaEntry{}, err
+	}
+	if _, err := io.Copy(tw, r2); err != nil {
+		return FileDigest{}, types.DeltaEntry{}, fmt.Errorf("failed to write content for %s: %w", path, err)
+	}
+
+	status := types.DeltaAdded
+	if known {
+		status = types.DeltaModified
+	}
+	return digest, types.DeltaEntry{Path: path, Status: status}, nil
+}
+
+// writeHeader writes a tar header for path (relative to the archive root)
+// declaring size bytes of content to follow.
+func (w *ArchiveWriter) writeHeader(tw *tar.Writer, path string, size int64) error {
+	header := &tar.Header{
+		Name:       filepath.Join(w.baseDir, path),
+		Size:       size,
+		Mode:       0644,
+		ModTime:    w.ts,
+		AccessTime: w.ts,
+		ChangeTime: w.ts,
+		Typeflag:   tar.TypeReg,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write header for %s: %w", path, err)
+	}
+	return nil
+}
+
+// peekMaxSize opens (and immediately closes) every registered source to
+// determine the largest entry size, without reading any content. It is used
+// to decide whether gzip compression should run in parallel.
+func (w *ArchiveWriter) peekMaxSize() (int64, error) {
+	var max int64
+	for p, src := range w.sources {
+		r, size, err := src.Open()
+		if err != nil {
+			return 0, fmt.Errorf("failed to open source for %s: %w", p, err)
+		}
+		r.Close()
+		if size > max {
+			max = size
+		}
+	}
+	return max, nil
+}
+
+// filename returns the archive's on-disk name for the configured compression.
+func (w *ArchiveWriter) filename() string {
+	switch w.compression {
+	case CompressionZstd:
+		return w.baseDir + ".tar.zst"
+	case CompressionNone:
+		return w.baseDir + ".tar"
+	default:
+		return w.baseDir + ".tar.gz"
+	}
+}
+
+// newCompressor returns the io.WriteCloser that sits between the tar writer
+// and the destination file. For gzip, if any entry is above
+// parallelGzipThreshold the whole archive is compressed with pgzip, so large
+// fuse/resource dumps compress across multiple cores instead of serializing
+// through a single gzip stream.
+func (w *ArchiveWriter) newCompressor(f io.Writer, maxEntrySize int64) (io.WriteCloser, error) {
+	switch w.compression {
+	case CompressionZstd:
+		return zstd.NewWriter(f)
+	case CompressionNone:
+		return nopWriteCloser{f}, nil
+	case CompressionGzip, "":
+		if maxEntrySize > parallelGzipThreshold {
+			return pgzip.NewWriter(f), nil
+		}
+		return gzip.NewWriter(f), nil
+	default:
+		return nil, fmt.Errorf("unsupported compression: %q", w.compression)
+	}
+}
+
+// nopWriteCloser adapts an io.Writer to io.WriteCloser for CompressionNone,
+// where the underlying file already owns its own Close.
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,131 @@
+// Package collector builds a types.BundleInput directly from a live
+// Kubernetes cluster, so callers don't have to assemble the resource graph,
+// logs, and metadata pkg/bundler expects by hand.
+package collector
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/mrhapile/fluid-diagnose-bundler/pkg/types"
+)
+
+// Collector produces a BundleInput ready to pass to bundler.Build.
+type Collector interface {
+	Collect(ctx context.Context) (types.BundleInput, error)
+}
+
+// CollectorOptions configures a KubeCollector.
+type CollectorOptions struct {
+	// Kubeconfig is the path to a kubeconfig file. If empty, the in-cluster
+	// config is used (i.e. the collector is assumed to run as a pod).
+	Kubeconfig string
+
+	// Namespace is the Dataset's namespace.
+	Namespace string
+	// DatasetName is the name of the Fluid Dataset to collect around.
+	DatasetName string
+
+	// LogTailLines caps how many lines are pulled from the end of each
+	// container's log. Defaults to defaultLogTailLines if zero.
+	LogTailLines int64
+	// LogSince bounds collected logs to the most recent window. Defaults to
+	// defaultLogSince if zero.
+	LogSince time.Duration
+}
+
+const (
+	defaultLogTailLines = int64(2000)
+	defaultLogSince     = 1 * time.Hour
+)
+
+func (o CollectorOptions) withDefaults() CollectorOptions {
+	if o.LogTailLines == 0 {
+		o.LogTailLines = defaultLogTailLines
+	}
+	if o.LogSince == 0 {
+		o.LogSince = defaultLogSince
+	}
+	return o
+}
+
+// KubeCollector is a Collector that walks the Fluid resource graph for a
+// single Dataset: Dataset -> Runtime -> StatefulSets/Pods -> PVC/PV ->
+// ConfigMaps -> Events, using the discovery and dynamic clients so it isn't
+// tied to a generated clientset for each Fluid runtime CRD (AlluxioRuntime,
+// JindoRuntime, ...).
+type KubeCollector struct {
+	opts      CollectorOptions
+	clientset kubernetes.Interface
+	dynClient dynamic.Interface
+	discovery discovery.DiscoveryInterface
+}
+
+// NewKubeCollector builds the discovery, dynamic, and typed clients from
+// opts.Kubeconfig (or the in-cluster config, if unset).
+func NewKubeCollector(opts CollectorOptions) (*KubeCollector, error) {
+	restConfig, err := loadRESTConfig(opts.Kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kube config: %w", err)
+	}
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build kubernetes client: %w", err)
+	}
+	dynClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build dynamic client: %w", err)
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build discovery client: %w", err)
+	}
+
+	return &KubeCollector{
+		opts:      opts.withDefaults(),
+		clientset: clientset,
+		dynClient: dynClient,
+		discovery: discoveryClient,
+	}, nil
+}
+
+func loadRESTConfig(kubeconfig string) (*rest.Config, error) {
+	if kubeconfig == "" {
+		return rest.InClusterConfig()
+	}
+	return clientcmd.BuildConfigFromFlags("", kubeconfig)
+}
+
+// Collect walks the Fluid resource graph rooted at opts.DatasetName,
+// fetches recent fuse/worker/master container logs, and reads cluster and
+// Fluid version info, assembling it all into a BundleInput.
+func (c *KubeCollector) Collect(ctx context.Context) (types.BundleInput, error) {
+	graph, pods, err := c.collectGraph(ctx)
+	if err != nil {
+		return types.BundleInput{}, fmt.Errorf("failed to collect resource graph: %w", err)
+	}
+
+	logs, err := c.collectLogs(ctx, pods)
+	if err != nil {
+		return types.BundleInput{}, fmt.Errorf("failed to collect pod logs: %w", err)
+	}
+
+	metadata, err := c.collectMetadata(ctx)
+	if err != nil {
+		return types.BundleInput{}, fmt.Errorf("failed to collect version metadata: %w", err)
+	}
+
+	return types.BundleInput{
+		Graph:    graph,
+		Metadata: metadata,
+		Logs:     logs,
+	}, nil
+}
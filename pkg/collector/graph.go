@@ -0,0 +1,180 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/mrhapile/fluid-diagnose-bundler/pkg/types"
+)
+
+var datasetGVR = schema.GroupVersionResource{Group: "data.fluid.io", Version: "v1alpha1", Resource: "datasets"}
+
+// runtimeRef is one entry of a Dataset's status.runtimes list.
+type runtimeRef struct {
+	Name      string
+	Namespace string
+	Type      string // e.g. "alluxio", "jindo" -> AlluxioRuntime, JindoRuntime
+}
+
+// collectGraph walks Dataset -> Runtime -> StatefulSets/Pods -> PVC/PV ->
+// ConfigMaps -> Events, returning the assembled ResourceGraph plus the pods
+// found along the way (collectLogs tails their fuse/worker/master containers).
+func (c *KubeCollector) collectGraph(ctx context.Context) (types.ResourceGraph, []corev1.Pod, error) {
+	dataset, err := c.dynClient.Resource(datasetGVR).Namespace(c.opts.Namespace).Get(ctx, c.opts.DatasetName, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get dataset %s/%s: %w", c.opts.Namespace, c.opts.DatasetName, err)
+	}
+
+	runtimeRefs := datasetRuntimeRefs(dataset)
+
+	var runtimes []interface{}
+	var statefulSets []interface{}
+	var pods []corev1.Pod
+	var podObjs []interface{}
+	var pvcs []interface{}
+	var pvs []interface{}
+	var configMaps []interface{}
+
+	for _, ref := range runtimeRefs {
+		runtimeGVR := schema.GroupVersionResource{
+			Group:    "data.fluid.io",
+			Version:  "v1alpha1",
+			Resource: strings.ToLower(ref.Type) + "runtimes",
+		}
+		runtime, err := c.dynClient.Resource(runtimeGVR).Namespace(ref.Namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get %s %s/%s: %w", runtimeGVR.Resource, ref.Namespace, ref.Name, err)
+		}
+		runtimes = append(runtimes, runtime.Object)
+
+		// Fluid labels every worker/fuse/master StatefulSet it manages for a
+		// runtime with "fluid.io/dataset=<namespace>-<name>".
+		selector := fmt.Sprintf("fluid.io/dataset=%s-%s", ref.Namespace, ref.Name)
+
+		stsList, err := c.clientset.AppsV1().StatefulSets(ref.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list statefulsets for %s: %w", selector, err)
+		}
+		for _, sts := range stsList.Items {
+			statefulSets = append(statefulSets, sts)
+		}
+
+		podList, err := c.clientset.CoreV1().Pods(ref.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list pods for %s: %w", selector, err)
+		}
+		for _, pod := range podList.Items {
+			pods = append(pods, pod)
+			podObjs = append(podObjs, pod)
+
+			for _, vol := range pod.Spec.Volumes {
+				if vol.PersistentVolumeClaim != nil {
+					pvc, err := c.clientset.CoreV1().PersistentVolumeClaims(ref.Namespace).Get(ctx, vol.PersistentVolumeClaim.ClaimName, metav1.GetOptions{})
+					if err != nil {
+						return nil, nil, fmt.Errorf("failed to get pvc %s: %w", vol.PersistentVolumeClaim.ClaimName, err)
+					}
+					pvcs = append(pvcs, pvc)
+
+					if pvc.Spec.VolumeName != "" {
+						pv, err := c.clientset.CoreV1().PersistentVolumes().Get(ctx, pvc.Spec.VolumeName, metav1.GetOptions{})
+						if err != nil {
+							return nil, nil, fmt.Errorf("failed to get pv %s: %w", pvc.Spec.VolumeName, err)
+						}
+						pvs = append(pvs, pv)
+					}
+				}
+				if vol.ConfigMap != nil {
+					cm, err := c.clientset.CoreV1().ConfigMaps(ref.Namespace).Get(ctx, vol.ConfigMap.Name, metav1.GetOptions{})
+					if err != nil {
+						return nil, nil, fmt.Errorf("failed to get configmap %s: %w", vol.ConfigMap.Name, err)
+					}
+					configMaps = append(configMaps, cm)
+				}
+			}
+		}
+	}
+
+	events, err := c.collectEvents(ctx, dataset.Object, podObjs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	graph := types.ResourceGraph{
+		"kind":         "Dataset",
+		"dataset":      dataset.Object,
+		"runtimes":     runtimes,
+		"statefulSets": statefulSets,
+		"pods":         podObjs,
+		"pvcs":         pvcs,
+		"pvs":          pvs,
+		"configMaps":   configMaps,
+		"events":       events,
+	}
+	return graph, pods, nil
+}
+
+// collectEvents gathers the Events involving the Dataset itself and every
+// pod collected for its runtimes.
+func (c *KubeCollector) collectEvents(ctx context.Context, dataset map[string]interface{}, pods []interface{}) ([]interface{}, error) {
+	var events []interface{}
+
+	namespace, _, _ := unstructured.NestedString(dataset, "metadata", "namespace")
+	name, _, _ := unstructured.NestedString(dataset, "metadata", "name")
+	uid, _, _ := unstructured.NestedString(dataset, "metadata", "uid")
+
+	refs := []struct{ namespace, name, uid string }{{namespace, name, uid}}
+	for _, p := range pods {
+		pod, ok := p.(corev1.Pod)
+		if !ok {
+			continue
+		}
+		refs = append(refs, struct{ namespace, name, uid string }{pod.Namespace, pod.Name, string(pod.UID)})
+	}
+
+	for _, ref := range refs {
+		list, err := c.clientset.CoreV1().Events(ref.namespace).List(ctx, metav1.ListOptions{
+			FieldSelector: fmt.Sprintf("involvedObject.name=%s,involvedObject.namespace=%s", ref.name, ref.namespace),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list events for %s/%s: %w", ref.namespace, ref.name, err)
+		}
+		for _, e := range list.Items {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+// datasetRuntimeRefs reads a Dataset's status.runtimes list (set by the
+// Fluid controller once it provisions a runtime for the dataset).
+func datasetRuntimeRefs(dataset *unstructured.Unstructured) []runtimeRef {
+	raw, found, err := unstructured.NestedSlice(dataset.Object, "status", "runtimes")
+	if err != nil || !found {
+		return nil
+	}
+
+	var refs []runtimeRef
+	for _, r := range raw {
+		m, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := m["name"].(string)
+		namespace, _ := m["namespace"].(string)
+		runtimeType, _ := m["type"].(string)
+		if name == "" || runtimeType == "" {
+			continue
+		}
+		if namespace == "" {
+			namespace = dataset.GetNamespace()
+		}
+		refs = append(refs, runtimeRef{Name: name, Namespace: namespace, Type: runtimeType})
+	}
+	return refs
+}
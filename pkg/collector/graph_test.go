@@ -0,0 +1,65 @@
+package collector
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestDatasetRuntimeRefs(t *testing.T) {
+	dataset := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":      "my-dataset",
+			"namespace": "fluid-ns",
+		},
+		"status": map[string]interface{}{
+			"runtimes": []interface{}{
+				map[string]interface{}{"name": "my-dataset", "type": "alluxio"},
+				map[string]interface{}{"name": "other", "namespace": "other-ns", "type": "jindo"},
+				map[string]interface{}{"name": "missing-type"},
+			},
+		},
+	}}
+
+	refs := datasetRuntimeRefs(dataset)
+	if len(refs) != 2 {
+		t.Fatalf("expected 2 refs (entry missing type is skipped), got %d: %+v", len(refs), refs)
+	}
+
+	if refs[0].Name != "my-dataset" || refs[0].Namespace != "fluid-ns" || refs[0].Type != "alluxio" {
+		t.Errorf("unexpected first ref: %+v", refs[0])
+	}
+	if refs[1].Name != "other" || refs[1].Namespace != "other-ns" || refs[1].Type != "jindo" {
+		t.Errorf("unexpected second ref: %+v", refs[1])
+	}
+}
+
+func TestDatasetRuntimeRefsNoStatus(t *testing.T) {
+	dataset := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "my-dataset", "namespace": "fluid-ns"},
+	}}
+
+	if refs := datasetRuntimeRefs(dataset); refs != nil {
+		t.Errorf("expected nil refs for a dataset with no status.runtimes, got %+v", refs)
+	}
+}
+
+func TestPodRole(t *testing.T) {
+	cases := []struct {
+		podName  string
+		wantRole string
+		wantOK   bool
+	}{
+		{"my-dataset-fuse-abcde", "fuse", true},
+		{"my-dataset-worker-0", "worker", true},
+		{"my-dataset-master-0", "master", true},
+		{"my-dataset-controller-manager-7d9c", "", false},
+	}
+
+	for _, tc := range cases {
+		role, ok := podRole(tc.podName)
+		if role != tc.wantRole || ok != tc.wantOK {
+			t.Errorf("podRole(%q) = (%q, %v), want (%q, %v)", tc.podName, role, ok, tc.wantRole, tc.wantOK)
+		}
+	}
+}
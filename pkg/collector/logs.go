@@ -0,0 +1,69 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// podRoleSuffixes maps the pod-name suffix Fluid gives each runtime
+// component to the role name its log is filed under.
+var podRoleSuffixes = map[string]string{
+	"-fuse":   "fuse",
+	"-worker": "worker",
+	"-master": "master",
+}
+
+// collectLogs tails recent container logs for every fuse/worker/master pod
+// in pods, keyed as "<role>/<pod>-<container>.log" (e.g.
+// "fuse/dataset-fuse-0-fuse.log"), to disambiguate pods sharing a role and
+// containers within a pod.
+func (c *KubeCollector) collectLogs(ctx context.Context, pods []corev1.Pod) (map[string][]byte, error) {
+	logs := make(map[string][]byte)
+	sinceSeconds := int64(c.opts.LogSince.Seconds())
+
+	for _, pod := range pods {
+		role, ok := podRole(pod.Name)
+		if !ok {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			content, err := c.tailContainerLog(ctx, pod.Namespace, pod.Name, container.Name, sinceSeconds)
+			if err != nil {
+				return nil, fmt.Errorf("failed to tail log for %s/%s container %s: %w", pod.Namespace, pod.Name, container.Name, err)
+			}
+			key := fmt.Sprintf("%s/%s-%s.log", role, pod.Name, container.Name)
+			logs[key] = content
+		}
+	}
+	return logs, nil
+}
+
+func (c *KubeCollector) tailContainerLog(ctx context.Context, namespace, podName, containerName string, sinceSeconds int64) ([]byte, error) {
+	req := c.clientset.CoreV1().Pods(namespace).GetLogs(podName, &corev1.PodLogOptions{
+		Container:    containerName,
+		TailLines:    &c.opts.LogTailLines,
+		SinceSeconds: &sinceSeconds,
+	})
+	stream, err := req.Stream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+	return io.ReadAll(stream)
+}
+
+// podRole classifies a pod by its Fluid-assigned name suffix (e.g.
+// "dataset-fuse-abcde" -> "fuse"), returning ok=false for pods that aren't
+// one of the runtime's fuse/worker/master components.
+func podRole(podName string) (string, bool) {
+	for suffix, role := range podRoleSuffixes {
+		if strings.Contains(podName, suffix) {
+			return role, true
+		}
+	}
+	return "", false
+}
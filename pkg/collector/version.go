@@ -0,0 +1,71 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/mrhapile/fluid-diagnose-bundler/pkg/types"
+)
+
+// fluidControllerNamespace and fluidControllerDeployment locate the
+// deployment Fluid installs its dataset controller as, whose image tag is
+// taken as the cluster's installed Fluid version.
+const (
+	fluidControllerNamespace  = "fluid-system"
+	fluidControllerDeployment = "fluid-dataset-controller"
+)
+
+// collectMetadata reads the cluster's Kubernetes version from the
+// discovery client and the installed Fluid version from the dataset
+// controller deployment's image tag.
+func (c *KubeCollector) collectMetadata(ctx context.Context) (types.BundleMetadata, error) {
+	serverVersion, err := c.discovery.ServerVersion()
+	if err != nil {
+		return types.BundleMetadata{}, fmt.Errorf("failed to get server version: %w", err)
+	}
+
+	fluidVersion, err := c.fluidControllerVersion(ctx)
+	if err != nil {
+		return types.BundleMetadata{}, err
+	}
+
+	return types.BundleMetadata{
+		CreationTimestamp: time.Now(),
+		FluidVersion:      fluidVersion,
+		K8sVersion:        serverVersion.GitVersion,
+	}, nil
+}
+
+// fluidControllerVersion extracts the image tag of the dataset controller
+// deployment's first container (e.g. "fluidcloudnative/dataset-controller:v1.0.3" -> "v1.0.3").
+func (c *KubeCollector) fluidControllerVersion(ctx context.Context) (string, error) {
+	deployment, err := c.clientset.AppsV1().Deployments(fluidControllerNamespace).Get(ctx, fluidControllerDeployment, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get %s/%s: %w", fluidControllerNamespace, fluidControllerDeployment, err)
+	}
+	if len(deployment.Spec.Template.Spec.Containers) == 0 {
+		return "", fmt.Errorf("%s/%s has no containers", fluidControllerNamespace, fluidControllerDeployment)
+	}
+
+	return imageTag(deployment.Spec.Template.Spec.Containers[0].Image), nil
+}
+
+// imageTag extracts the tag from an image reference, e.g.
+// "fluidcloudnative/dataset-controller:v1.0.3" -> "v1.0.3". The repository
+// path is stripped first so a registry host's "host:port" isn't mistaken
+// for a tag (e.g. "registry:5000/fluid/controller" has no tag). Returns the
+// image unchanged if it carries no tag.
+func imageTag(image string) string {
+	repo := image
+	if idx := strings.LastIndex(image, "/"); idx >= 0 {
+		repo = image[idx+1:]
+	}
+	if idx := strings.LastIndex(repo, ":"); idx >= 0 {
+		return repo[idx+1:]
+	}
+	return image
+}
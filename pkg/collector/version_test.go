@@ -0,0 +1,22 @@
+package collector
+
+import "testing"
+
+func TestImageTag(t *testing.T) {
+	cases := []struct {
+		image string
+		want  string
+	}{
+		{"fluidcloudnative/dataset-controller:v1.0.3", "v1.0.3"},
+		{"registry:5000/fluid/controller:v1.2.3", "v1.2.3"},
+		{"registry:5000/fluid/controller", "registry:5000/fluid/controller"},
+		{"my-image:latest", "latest"},
+		{"my-image", "my-image"},
+	}
+
+	for _, tc := range cases {
+		if got := imageTag(tc.image); got != tc.want {
+			t.Errorf("imageTag(%q) = %q, want %q", tc.image, got, tc.want)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package types
 
-import "time"
+import (
+	"io"
+	"time"
+)
 
 // ResourceGraph represents the connected state of Kubernetes and Fluid resources.
 // It is expected to be a map of resource kinds to lists of resources, or a more complex graph object.
@@ -27,6 +30,14 @@ type BundleMetadata struct {
 	Environment       string    `json:"environment,omitempty"` // e.g., "production", "ci"
 }
 
+// Source lets large files (fuse logs, resource dumps) be supplied to the
+// bundler as a stream instead of being fully loaded into memory up front.
+// Open may be called more than once (e.g. once to report size, once to
+// stream content) and must return a fresh reader each time.
+type Source interface {
+	Open() (io.ReadCloser, int64, error)
+}
+
 // BundleInput is the input payload for creating a diagnostic bundle.
 type BundleInput struct {
 	Graph     ResourceGraph     // The raw resource state
@@ -34,6 +45,13 @@ type BundleInput struct {
 	Metadata  BundleMetadata    // Contextual metadata
 	Logs      map[string][]byte // Log file contents (filename -> content)
 	Resources map[string]string // Additional raw resource YAMLs (path -> content) if needed
+
+	// LogSources optionally supplies large log files as streams instead of
+	// fully-buffered content, keeping multi-hundred-MB fuse logs out of memory.
+	LogSources map[string]Source
+	// ResourceSources optionally supplies large raw resource dumps as
+	// streams, analogous to LogSources.
+	ResourceSources map[string]Source
 }
 
 // BundleResult represents the output of a successful bundling operation.
@@ -42,4 +60,26 @@ type BundleResult struct {
 	FileCount   int            // Total number of files archived
 	Manifest    BundleManifest // The manifest generated for the archive
 	SizeBytes   int64          // Size of the archive in bytes
+
+	// OCILayoutPath is the absolute path to the OCI image layout directory,
+	// set only when the bundle was built with WithOCIExport().
+	OCILayoutPath string `json:"ociLayoutPath,omitempty"`
+
+	// RedactionReport lists every field a redaction policy matched, set
+	// only when the bundle was built with redaction enabled. When built
+	// with WithRedactionDryRun, these fields were left unmodified; otherwise
+	// they reflect what was actually redacted or dropped.
+	RedactionReport *DryRunReport `json:"redactionReport,omitempty"`
+
+	// SignaturePath is the absolute path to the detached manifest signature
+	// (manifest.sig), set only when the bundle was built with WithSigning.
+	SignaturePath string `json:"signaturePath,omitempty"`
+	// SignaturesIndexPath is the absolute path to signatures.json, set only
+	// when the bundle was built with WithSigning.
+	SignaturesIndexPath string `json:"signaturesIndexPath,omitempty"`
+
+	// DeltaEntries lists every file's status relative to the baseline, set
+	// only when the bundle was built with WithBaseline. Also written into
+	// the archive itself as delta.json.
+	DeltaEntries []DeltaEntry `json:"deltaEntries,omitempty"`
 }
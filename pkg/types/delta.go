@@ -0,0 +1,25 @@
+package types
+
+// DeltaStatus describes a single file's relationship to the baseline bundle
+// an incremental bundle was diffed against (see bundler.WithBaseline).
+type DeltaStatus string
+
+const (
+	DeltaUnchanged DeltaStatus = "unchanged"
+	DeltaModified  DeltaStatus = "modified"
+	DeltaAdded     DeltaStatus = "added"
+	DeltaRemoved   DeltaStatus = "removed"
+)
+
+// DeltaEntry records one file's delta status, written as part of an
+// incremental bundle's delta.json.
+type DeltaEntry struct {
+	Path   string      `json:"path"`
+	Status DeltaStatus `json:"status"`
+
+	// RefSHA256 is the baseline's SHA256 for this path. Set for
+	// "unchanged" entries, whose archive content is a zero-byte
+	// placeholder, and for "removed" entries, which have no content at
+	// all in the incremental archive.
+	RefSHA256 string `json:"refSha256,omitempty"`
+}
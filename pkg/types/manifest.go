@@ -16,8 +16,9 @@ type BundleManifest struct {
 	// Files lists all files in the archive with their metadata.
 	Files []FileEntry `json:"files"`
 
-	// ContentHash is the SHA256 checksum of the entire archive content (excluding the archive wrapper itself).
-	// Note: In practice, this might refer to a hash of the manifest or a deterministic hash of the file contents.
+	// ContentHash is a Merkle root over the sorted (path, sha256) pairs of
+	// every file in Files, stable across reruns regardless of the order
+	// files were added in. See bundler.ComputeContentHash.
 	ContentHash string `json:"contentHash"`
 }
 
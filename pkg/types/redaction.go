@@ -0,0 +1,19 @@
+package types
+
+// RedactAction records what a redaction pass did -- or, in dry-run mode,
+// would do -- to a single field within a bundled document.
+type RedactAction struct {
+	// Path is the dotted field path within the document, e.g. "spec.data.password".
+	Path string
+	// Reason is which kind of rule matched: "keyRule", "valueRule",
+	// "selector", or "kindOverride".
+	Reason string
+	// Action is what happened to the field: "redact" or "drop".
+	Action string
+}
+
+// DryRunReport lists every RedactAction a redaction pass found, without
+// necessarily having mutated the underlying content.
+type DryRunReport struct {
+	Actions []RedactAction
+}